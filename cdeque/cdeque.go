@@ -1,20 +1,44 @@
 package cdeque
 
 import (
-	"data_structures/deque"
+	"github.com/Shreyas-Adireddy/data_structures"
+	"github.com/Shreyas-Adireddy/data_structures/deque"
+	"iter"
 	"sync"
+	"sync/atomic"
 )
 
+var (
+	// ErrEmpty is returned by the Pop and Peek methods when the deque has no elements.
+	ErrEmpty = datastructures.ErrEmpty
+	// ErrClosed is returned when popping from a closed and drained deque, and is the panic value when adding to a
+	// closed deque.
+	ErrClosed = datastructures.ErrClosed
+	// ErrIndexOutOfRange is returned by Snapshot.At when an index is outside of the snapshot.
+	ErrIndexOutOfRange = datastructures.ErrIndexOutOfRange
+)
+
+var _ datastructures.Double[int] = (*ConcurrentDeque[int])(nil)
+
 // ConcurrentDeque is a thread-safe double-ended queue.
 type ConcurrentDeque[T any] struct {
-	dq *deque.Deque[T]
-	rw sync.RWMutex
+	dq     *deque.Deque[T]
+	rw     sync.RWMutex
+	closed bool
+	shared atomic.Bool // Whether a Snapshot refers to dq, in which case it is copied before the next write
+}
+
+// New creates a new ConcurrentDeque. opts configure the underlying deque.Deque.
+func New[T any](opts ...deque.Option) *ConcurrentDeque[T] {
+	return &ConcurrentDeque[T]{
+		dq: deque.New[T](opts...),
+	}
 }
 
-// New creates a new ConcurrentDeque.
-func New[T any]() *ConcurrentDeque[T] {
+// NewWithCapacity creates a new ConcurrentDeque whose underlying deque.Deque is preallocated for capacity elements.
+func NewWithCapacity[T any](capacity int, opts ...deque.Option) *ConcurrentDeque[T] {
 	return &ConcurrentDeque[T]{
-		dq: deque.New[T](),
+		dq: deque.NewWithCapacity[T](capacity, opts...),
 	}
 }
 
@@ -32,34 +56,101 @@ func (cd *ConcurrentDeque[T]) Size() int {
 	return cd.dq.Size()
 }
 
-// AddFront adds an element to the front of the deque.
+// Cap returns the number of elements the deque holds before it has to grow.
+func (cd *ConcurrentDeque[T]) Cap() int {
+	cd.rw.RLock()
+	defer cd.rw.RUnlock()
+	return cd.dq.Cap()
+}
+
+// AddFront adds an element to the front of the deque. Panics with ErrClosed if the deque is closed.
 func (cd *ConcurrentDeque[T]) AddFront(value T) {
+	if err := cd.TryAddFront(value); err != nil {
+		panic(err)
+	}
+}
+
+// TryAddFront is like AddFront but returns ErrClosed instead of panicking if the deque is closed.
+func (cd *ConcurrentDeque[T]) TryAddFront(value T) error {
 	cd.rw.Lock()
 	defer cd.rw.Unlock()
+	if cd.closed {
+		return ErrClosed
+	}
+	cd.detach()
 	cd.dq.AddFront(value)
+	return nil
 }
 
-// AddRear adds an element to the rear of the deque.
+// AddRear adds an element to the rear of the deque. Panics with ErrClosed if the deque is closed.
 func (cd *ConcurrentDeque[T]) AddRear(value T) {
+	if err := cd.TryAddRear(value); err != nil {
+		panic(err)
+	}
+}
+
+// TryAddRear is like AddRear but returns ErrClosed instead of panicking if the deque is closed.
+func (cd *ConcurrentDeque[T]) TryAddRear(value T) error {
 	cd.rw.Lock()
 	defer cd.rw.Unlock()
+	if cd.closed {
+		return ErrClosed
+	}
+	cd.detach()
 	cd.dq.AddRear(value)
+	return nil
+}
+
+// AddRearAll adds values to the rear of the deque in order under a single lock. Panics with ErrClosed if the deque
+// is closed.
+func (cd *ConcurrentDeque[T]) AddRearAll(values ...T) {
+	cd.rw.Lock()
+	defer cd.rw.Unlock()
+	if cd.closed {
+		panic(ErrClosed)
+	}
+	cd.detach()
+	cd.dq.AddRearAll(values...)
 }
 
-// PopFront removes and returns an element from the front of the deque.
+// PopFront removes and returns an element from the front of the deque. Returns ErrClosed once a closed deque
+// is drained.
 func (cd *ConcurrentDeque[T]) PopFront() (T, error) {
 	cd.rw.Lock()
 	defer cd.rw.Unlock()
+	if cd.closed && cd.dq.IsEmpty() {
+		var zero T
+		return zero, ErrClosed
+	}
+	cd.detach()
 	return cd.dq.PopFront()
 }
 
-// PopRear removes and returns an element from the rear of the deque.
+// PopRear removes and returns an element from the rear of the deque. Returns ErrClosed once a closed deque
+// is drained.
 func (cd *ConcurrentDeque[T]) PopRear() (T, error) {
 	cd.rw.Lock()
 	defer cd.rw.Unlock()
+	if cd.closed && cd.dq.IsEmpty() {
+		var zero T
+		return zero, ErrClosed
+	}
+	cd.detach()
 	return cd.dq.PopRear()
 }
 
+// PopFrontN removes up to n elements from the front of the deque under a single lock and returns them, front first.
+// Returns ErrClosed once a closed deque is drained.
+func (cd *ConcurrentDeque[T]) PopFrontN(n int) ([]T, error) {
+	cd.rw.Lock()
+	defer cd.rw.Unlock()
+	if cd.closed && cd.dq.IsEmpty() {
+		return []T{}, ErrClosed
+	}
+	cd.detach()
+	return cd.dq.PopFrontN(n), nil
+}
+
 // PeekFront returns the front element without removing it.
 func (cd *ConcurrentDeque[T]) PeekFront() (T, error) {
 	cd.rw.RLock()
@@ -78,7 +169,7 @@ func (cd *ConcurrentDeque[T]) PeekRear() (T, error) {
 func (cd *ConcurrentDeque[T]) Clear() {
 	cd.rw.Lock()
 	defer cd.rw.Unlock()
-	cd.dq.Clear()
+	cd.reset()
 }
 
 // ToSlice converts the deque to a slice and returns it.
@@ -87,3 +178,54 @@ func (cd *ConcurrentDeque[T]) ToSlice() []T {
 	defer cd.rw.RUnlock()
 	return cd.dq.ToSlice()
 }
+
+// All returns an iterator over a snapshot of the deque from front to rear. The snapshot is taken under
+// the read lock when iteration starts, so the loop body may safely modify the deque.
+func (cd *ConcurrentDeque[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range cd.ToSlice() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over a snapshot of the deque from rear to front.
+func (cd *ConcurrentDeque[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		values := cd.ToSlice()
+		for i := len(values) - 1; i >= 0; i-- {
+			if !yield(values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns an iterator over the positions and elements of a snapshot of the deque, where position 0 is
+// the front.
+func (cd *ConcurrentDeque[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, value := range cd.ToSlice() {
+			if !yield(i, value) {
+				return
+			}
+		}
+	}
+}
+
+// Close stops the deque from accepting new elements. Elements already in the deque can still be popped, after
+// which pops return ErrClosed. Closing twice is a no-op.
+func (cd *ConcurrentDeque[T]) Close() {
+	cd.rw.Lock()
+	defer cd.rw.Unlock()
+	cd.closed = true
+}
+
+// IsClosed checks if the deque has been closed.
+func (cd *ConcurrentDeque[T]) IsClosed() bool {
+	cd.rw.RLock()
+	defer cd.rw.RUnlock()
+	return cd.closed
+}
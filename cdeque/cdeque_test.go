@@ -1 +1,184 @@
 package cdeque
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentDeque(t *testing.T) {
+	cd := New[int]()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 250; j++ {
+				if i%2 == 0 {
+					cd.AddFront(j)
+				} else {
+					cd.AddRear(j)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if cd.Size() != 1000 {
+		t.Errorf("Expected deque size to be 1000, got %d", cd.Size())
+	}
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 250; j++ {
+				var err error
+				if i%2 == 0 {
+					_, err = cd.PopFront()
+				} else {
+					_, err = cd.PopRear()
+				}
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if !cd.IsEmpty() {
+		t.Errorf("Expected deque to be empty, got size %d", cd.Size())
+	}
+}
+
+func TestClose(t *testing.T) {
+	cd := New[int]()
+	if err := cd.TryAddRear(2); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := cd.TryAddFront(1); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	cd.Close()
+	if !cd.IsClosed() {
+		t.Error("Expected deque to be closed")
+	}
+
+	if val, err := cd.PopRear(); err != nil || val != 2 {
+		t.Errorf("Expected PopRear to return 2, got %v (error: %v)", val, err)
+	}
+	if val, err := cd.PopFront(); err != nil || val != 1 {
+		t.Errorf("Expected PopFront to return 1, got %v (error: %v)", val, err)
+	}
+	if _, err := cd.PopFront(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+	if _, err := cd.PopFrontN(2); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected PopFrontN to return ErrClosed, got %v", err)
+	}
+	if err := cd.TryAddFront(3); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected TryAddFront to return ErrClosed, got %v", err)
+	}
+	if err := cd.TryAddRear(3); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected TryAddRear to return ErrClosed, got %v", err)
+	}
+
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected panic with ErrClosed, got %v", err)
+		}
+	}()
+	cd.AddRear(3)
+}
+
+func TestEncoding(t *testing.T) {
+	cd := New[int]()
+	cd.AddRear(2)
+	cd.AddFront(1)
+	cd.AddRear(3)
+	data, err := json.Marshal(cd)
+	if err != nil || string(data) != "[1,2,3]" {
+		t.Errorf("Expected MarshalJSON to return [1,2,3], got %s (error: %v)", data, err)
+	}
+
+	var fromJSON ConcurrentDeque[int]
+	if err := json.Unmarshal(data, &fromJSON); err != nil || !slices.Equal(fromJSON.ToSlice(), []int{1, 2, 3}) {
+		t.Errorf("Expected UnmarshalJSON to restore [1 2 3], got %v (error: %v)", fromJSON.ToSlice(), err)
+	}
+
+	data, err = cd.GobEncode()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var fromGob ConcurrentDeque[int]
+	if err := fromGob.GobDecode(data); err != nil || !slices.Equal(fromGob.ToSlice(), []int{1, 2, 3}) {
+		t.Errorf("Expected GobDecode to restore [1 2 3], got %v (error: %v)", fromGob.ToSlice(), err)
+	}
+
+	cd.Close()
+	if err := cd.UnmarshalBinary(data); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	cd := New[int]()
+	cd.AddRear(2)
+	cd.AddRear(3)
+	cd.AddFront(1)
+	snap := cd.Snapshot()
+
+	cd.PopFront()
+	cd.AddRear(4)
+	cd.AddFront(0)
+	if got := snap.ToSlice(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Expected the snapshot to hold [1 2 3], got %v", got)
+	}
+	if got := cd.ToSlice(); !slices.Equal(got, []int{0, 2, 3, 4}) {
+		t.Errorf("Expected the deque to hold [0 2 3 4], got %v", got)
+	}
+	if val, err := snap.At(1); err != nil || val != 2 {
+		t.Errorf("Expected At(1) to return 2, got %v (error: %v)", val, err)
+	}
+	if _, err := snap.At(-1); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Expected ErrIndexOutOfRange, got %v", err)
+	}
+	if front, _ := snap.PeekFront(); front != 1 {
+		t.Errorf("Expected PeekFront to return 1, got %v", front)
+	}
+	if rear, _ := snap.PeekRear(); rear != 3 {
+		t.Errorf("Expected PeekRear to return 3, got %v", rear)
+	}
+	for i, v := range snap.Enumerate() {
+		if v != i+1 {
+			t.Errorf("Expected position %d to hold %d, got %d", i, i+1, v)
+		}
+	}
+
+	// Clearing right after a snapshot swaps in a new array rather than emptying the shared one
+	cleared := cd.Snapshot()
+	cd.Clear()
+	if got := cleared.ToSlice(); !slices.Equal(got, []int{0, 2, 3, 4}) {
+		t.Errorf("Expected the snapshot to hold [0 2 3 4] after Clear, got %v", got)
+	}
+	if !cd.IsEmpty() {
+		t.Errorf("Expected deque to be empty, got %v", cd.ToSlice())
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			cd.AddRear(i)
+			cd.PopFront()
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if s := cd.Snapshot(); s.Size() != len(s.ToSlice()) {
+			t.Errorf("Expected the snapshot to stay consistent")
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,52 @@
+package cdeque
+
+import (
+	"github.com/Shreyas-Adireddy/data_structures/deque"
+	"github.com/Shreyas-Adireddy/data_structures/internal/codec"
+)
+
+// MarshalJSON encodes a snapshot of the deque as a JSON array from front to rear, taken under the read lock.
+func (cd *ConcurrentDeque[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalJSON(cd.ToSlice())
+}
+
+// UnmarshalJSON replaces the contents of the deque with a JSON array, the first element becoming the front.
+func (cd *ConcurrentDeque[T]) UnmarshalJSON(data []byte) error {
+	return codec.UnmarshalJSON(data, cd.replace)
+}
+
+// GobEncode encodes a snapshot of the deque from front to rear, taken under the read lock.
+func (cd *ConcurrentDeque[T]) GobEncode() ([]byte, error) {
+	return codec.GobEncode(cd.ToSlice())
+}
+
+// GobDecode replaces the contents of the deque with data encoded by GobEncode.
+func (cd *ConcurrentDeque[T]) GobDecode(data []byte) error {
+	return codec.GobDecode(data, cd.replace)
+}
+
+// MarshalBinary encodes the deque with encoding/gob, same as GobEncode.
+func (cd *ConcurrentDeque[T]) MarshalBinary() ([]byte, error) {
+	return cd.GobEncode()
+}
+
+// UnmarshalBinary replaces the contents of the deque with data encoded by MarshalBinary.
+func (cd *ConcurrentDeque[T]) UnmarshalBinary(data []byte) error {
+	return cd.GobDecode(data)
+}
+
+// replace replaces the contents of the deque with values, the first element becoming the front. Works on a zero
+// ConcurrentDeque too. Returns ErrClosed if the deque is closed.
+func (cd *ConcurrentDeque[T]) replace(values []T) error {
+	cd.rw.Lock()
+	defer cd.rw.Unlock()
+	if cd.closed {
+		return ErrClosed
+	}
+	if cd.dq == nil {
+		cd.dq = deque.New[T]()
+	}
+	cd.reset()
+	cd.dq.AddRearAll(values...)
+	return nil
+}
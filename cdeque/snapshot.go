@@ -0,0 +1,90 @@
+package cdeque
+
+import (
+	"github.com/Shreyas-Adireddy/data_structures/deque"
+	"iter"
+)
+
+// Snapshot is a read-only view of a ConcurrentDeque as it was when Snapshot was called. It shares the deque's
+// backing array until the deque is next written to, and reading it takes no lock. Writers pay for the copy instead:
+// the first write after Snapshot copies the array in O(n) while holding the write lock.
+type Snapshot[T any] struct {
+	dq *deque.Deque[T]
+}
+
+// Snapshot returns a read-only view of the deque in O(1). Instead of holding the read lock while the contents are
+// copied like ToSlice does, the deque copies its backing array on the first write after a snapshot, under the write
+// lock. Clear doesn't need the old contents, so it allocates a new array instead.
+func (cd *ConcurrentDeque[T]) Snapshot() *Snapshot[T] {
+	cd.rw.RLock()
+	defer cd.rw.RUnlock()
+	cd.shared.Store(true)
+	return &Snapshot[T]{dq: cd.dq}
+}
+
+// detach gives the deque its own copy of the backing array if a Snapshot refers to it. Must be called with cd.rw
+// held for writing, before modifying cd.dq.
+func (cd *ConcurrentDeque[T]) detach() {
+	if cd.shared.Load() {
+		cd.dq = cd.dq.Clone()
+		cd.shared.Store(false)
+	}
+}
+
+// reset empties the deque. Must be called with cd.rw held for writing. Unlike detach followed by Clear it doesn't
+// copy contents a Snapshot refers to only to throw them away.
+func (cd *ConcurrentDeque[T]) reset() {
+	if cd.shared.Load() {
+		// Clear allocates a new backing array, so emptying a copy of the struct leaves the snapshot's alone
+		dq := *cd.dq
+		cd.dq = &dq
+		cd.shared.Store(false)
+	}
+	cd.dq.Clear()
+}
+
+// PeekFront returns the front element of the snapshot.
+func (s *Snapshot[T]) PeekFront() (T, error) {
+	return s.dq.PeekFront()
+}
+
+// PeekRear returns the rear element of the snapshot.
+func (s *Snapshot[T]) PeekRear() (T, error) {
+	return s.dq.PeekRear()
+}
+
+// At returns the element at position i of the snapshot, where position 0 is the front. Returns ErrIndexOutOfRange
+// if i is outside of the snapshot.
+func (s *Snapshot[T]) At(i int) (T, error) {
+	return s.dq.At(i)
+}
+
+// Size returns the number of elements in the snapshot.
+func (s *Snapshot[T]) Size() int {
+	return s.dq.Size()
+}
+
+// IsEmpty checks if the snapshot is empty.
+func (s *Snapshot[T]) IsEmpty() bool {
+	return s.dq.IsEmpty()
+}
+
+// ToSlice returns the elements of the snapshot from front to rear.
+func (s *Snapshot[T]) ToSlice() []T {
+	return s.dq.ToSlice()
+}
+
+// All returns an iterator over the elements of the snapshot from front to rear, without copying them.
+func (s *Snapshot[T]) All() iter.Seq[T] {
+	return s.dq.All()
+}
+
+// Backward returns an iterator over the elements of the snapshot from rear to front.
+func (s *Snapshot[T]) Backward() iter.Seq[T] {
+	return s.dq.Backward()
+}
+
+// Enumerate returns an iterator over the positions and elements of the snapshot, where position 0 is the front.
+func (s *Snapshot[T]) Enumerate() iter.Seq2[int, T] {
+	return s.dq.Enumerate()
+}
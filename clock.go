@@ -0,0 +1,18 @@
+package datastructures
+
+import "time"
+
+// Clock tells the time and waits for durations. Containers that schedule work take one as an option, so tests can
+// supply a fake clock and control time without sleeping.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock backed by the time package. It is the default wherever a Clock can be supplied.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
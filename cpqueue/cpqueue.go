@@ -0,0 +1,142 @@
+package cpqueue
+
+import (
+	"cmp"
+	"context"
+	"github.com/Shreyas-Adireddy/data_structures"
+	"github.com/Shreyas-Adireddy/data_structures/internal/notify"
+	"github.com/Shreyas-Adireddy/data_structures/pqueue"
+	"sync"
+)
+
+var (
+	// ErrEmpty is returned by Pop and Peek when the priority queue has no elements.
+	ErrEmpty = datastructures.ErrEmpty
+	// ErrInvalidHandle is returned when a handle was already popped or removed, or belongs to another queue. It is
+	// the same error as pqueue.ErrInvalidHandle.
+	ErrInvalidHandle = pqueue.ErrInvalidHandle
+)
+
+var _ datastructures.Container[int] = (*ConcurrentPriorityQueue[int])(nil)
+
+// ConcurrentPriorityQueue is a thread-safe priority queue.
+type ConcurrentPriorityQueue[T any] struct {
+	pq      *pqueue.PriorityQueue[T]
+	rw      sync.RWMutex
+	waiters notify.List // Goroutines blocked in PopWait, guarded by rw
+}
+
+// New creates a new ConcurrentPriorityQueue ordered by less.
+func New[T any](less func(a, b T) bool) *ConcurrentPriorityQueue[T] {
+	return &ConcurrentPriorityQueue[T]{
+		pq: pqueue.New(less),
+	}
+}
+
+// NewOrdered creates a new ConcurrentPriorityQueue that pops the smallest element first.
+func NewOrdered[T cmp.Ordered]() *ConcurrentPriorityQueue[T] {
+	return &ConcurrentPriorityQueue[T]{
+		pq: pqueue.NewOrdered[T](),
+	}
+}
+
+// Push adds an element to the priority queue and returns a handle to it.
+func (cpq *ConcurrentPriorityQueue[T]) Push(value T) *pqueue.Handle[T] {
+	cpq.rw.Lock()
+	defer cpq.rw.Unlock()
+	h := cpq.pq.Push(value)
+	cpq.waiters.Signal()
+	return h
+}
+
+// Pop removes and returns the element with the highest priority.
+func (cpq *ConcurrentPriorityQueue[T]) Pop() (T, error) {
+	cpq.rw.Lock()
+	defer cpq.rw.Unlock()
+	return cpq.pq.Pop()
+}
+
+// PopWait removes and returns the element with the highest priority, waiting for an element to be pushed if the
+// priority queue is empty. Returns ctx.Err() if the context is done first.
+func (cpq *ConcurrentPriorityQueue[T]) PopWait(ctx context.Context) (T, error) {
+	cpq.rw.Lock()
+	defer cpq.rw.Unlock()
+	for cpq.pq.IsEmpty() {
+		if err := cpq.waiters.Wait(ctx, &cpq.rw); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+	return cpq.pq.Pop()
+}
+
+// Peek returns the element with the highest priority without removing it.
+func (cpq *ConcurrentPriorityQueue[T]) Peek() (T, error) {
+	cpq.rw.RLock()
+	defer cpq.rw.RUnlock()
+	return cpq.pq.Peek()
+}
+
+// Update replaces the element h refers to and restores the heap order.
+func (cpq *ConcurrentPriorityQueue[T]) Update(h *pqueue.Handle[T], value T) error {
+	cpq.rw.Lock()
+	defer cpq.rw.Unlock()
+	return cpq.pq.Update(h, value)
+}
+
+// Fix restores the heap order after the priority of the element h refers to changed in place. The change must be
+// made while no other goroutine can read it, since the priority queue's lock doesn't cover the element itself.
+func (cpq *ConcurrentPriorityQueue[T]) Fix(h *pqueue.Handle[T]) error {
+	cpq.rw.Lock()
+	defer cpq.rw.Unlock()
+	return cpq.pq.Fix(h)
+}
+
+// Remove removes and returns the element h refers to.
+func (cpq *ConcurrentPriorityQueue[T]) Remove(h *pqueue.Handle[T]) (T, error) {
+	cpq.rw.Lock()
+	defer cpq.rw.Unlock()
+	return cpq.pq.Remove(h)
+}
+
+// Value returns the element h refers to. Unlike h.Value, it is safe to call while other goroutines update h.
+func (cpq *ConcurrentPriorityQueue[T]) Value(h *pqueue.Handle[T]) T {
+	cpq.rw.RLock()
+	defer cpq.rw.RUnlock()
+	return h.Value()
+}
+
+// Contains checks if h refers to an element still in the priority queue.
+func (cpq *ConcurrentPriorityQueue[T]) Contains(h *pqueue.Handle[T]) bool {
+	cpq.rw.RLock()
+	defer cpq.rw.RUnlock()
+	return cpq.pq.Contains(h)
+}
+
+// Size returns the number of elements in the priority queue.
+func (cpq *ConcurrentPriorityQueue[T]) Size() int {
+	cpq.rw.RLock()
+	defer cpq.rw.RUnlock()
+	return cpq.pq.Size()
+}
+
+// IsEmpty checks if the priority queue is empty.
+func (cpq *ConcurrentPriorityQueue[T]) IsEmpty() bool {
+	cpq.rw.RLock()
+	defer cpq.rw.RUnlock()
+	return cpq.pq.IsEmpty()
+}
+
+// Clear removes all elements from the priority queue.
+func (cpq *ConcurrentPriorityQueue[T]) Clear() {
+	cpq.rw.Lock()
+	defer cpq.rw.Unlock()
+	cpq.pq.Clear()
+}
+
+// ToSlice returns the elements of the priority queue in heap order.
+func (cpq *ConcurrentPriorityQueue[T]) ToSlice() []T {
+	cpq.rw.RLock()
+	defer cpq.rw.RUnlock()
+	return cpq.pq.ToSlice()
+}
@@ -0,0 +1,98 @@
+package cpqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentPriorityQueue(t *testing.T) {
+	cpq := NewOrdered[int]()
+	wg := sync.WaitGroup{}
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 250; i++ {
+				cpq.Push(g*250 + i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	if cpq.Size() != 1000 {
+		t.Errorf("Expected size to be 1000, got %d", cpq.Size())
+	}
+	for i := 0; i < 1000; i++ {
+		if val, err := cpq.Pop(); err != nil || val != i {
+			t.Fatalf("Expected Pop to return %d, got %v (error: %v)", i, val, err)
+		}
+	}
+	if _, err := cpq.Peek(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+}
+
+func TestHandles(t *testing.T) {
+	cpq := New(func(a, b int) bool { return a > b })
+	h := cpq.Push(1)
+	cpq.Push(2)
+	if err := cpq.Update(h, 3); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if top, _ := cpq.Peek(); top != 3 {
+		t.Errorf("Expected Peek to return 3, got %v", top)
+	}
+	if val, err := cpq.Remove(h); err != nil || val != 3 {
+		t.Errorf("Expected Remove to return 3, got %v (error: %v)", val, err)
+	}
+	if err := cpq.Fix(h); !errors.Is(err, ErrInvalidHandle) {
+		t.Errorf("Expected ErrInvalidHandle, got %v", err)
+	}
+}
+
+func TestPopWait(t *testing.T) {
+	cpq := NewOrdered[int]()
+	done := make(chan int)
+	go func() {
+		val, err := cpq.PopWait(context.Background())
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		done <- val
+	}()
+	time.Sleep(5 * time.Millisecond)
+	cpq.Push(7)
+	if val := <-done; val != 7 {
+		t.Errorf("Expected PopWait to return 7, got %v", val)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, err := cpq.PopWait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestValue(t *testing.T) {
+	cpq := NewOrdered[int]()
+	h := cpq.Push(0)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 100; i++ {
+			cpq.Update(h, i)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if v := cpq.Value(h); v < 0 || v > 100 {
+			t.Errorf("Expected Value to return a pushed or updated element, got %v", v)
+		}
+	}
+	wg.Wait()
+	if v := cpq.Value(h); v != 100 {
+		t.Errorf("Expected Value to return 100, got %v", v)
+	}
+}
@@ -1,35 +1,264 @@
 package cqueue
 
 import (
-	"data_structures/queue"
+	"context"
+	"github.com/Shreyas-Adireddy/data_structures"
+	"github.com/Shreyas-Adireddy/data_structures/internal/notify"
+	"github.com/Shreyas-Adireddy/data_structures/queue"
+	"iter"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+var (
+	// ErrEmpty is returned by Dequeue, Front and Back when the queue has no elements.
+	ErrEmpty = datastructures.ErrEmpty
+	// ErrFull is returned by TryEnqueue when a bounded queue has no room left.
+	ErrFull = datastructures.ErrFull
+	// ErrClosed is returned when reading from a closed and drained queue, or writing to a closed queue.
+	ErrClosed = datastructures.ErrClosed
+)
+
+var _ datastructures.FIFO[int] = (*ConcurrentQueue[int])(nil)
+
 // ConcurrentQueue is a thread-safe queue.
 type ConcurrentQueue[T any] struct {
-	q  *queue.Queue[T]
-	rw sync.RWMutex
+	q       *queue.Queue[T]
+	rw      sync.RWMutex
+	bound   int // Maximum number of elements, 0 if unbounded
+	closed  bool
+	waiters notify.List // Goroutines blocked waiting for an element, guarded by rw
+	space   notify.List // Goroutines blocked waiting for room in a bounded queue, guarded by rw
+	shared  atomic.Bool // Whether a Snapshot refers to q, in which case it is copied before the next write
+}
+
+// New creates a new ConcurrentQueue. opts configure the underlying queue.Queue.
+func New[T any](opts ...queue.Option) *ConcurrentQueue[T] {
+	return &ConcurrentQueue[T]{
+		q: queue.New[T](opts...),
+	}
+}
+
+// NewWithCapacity creates a new ConcurrentQueue whose underlying queue.Queue is preallocated for capacity elements.
+func NewWithCapacity[T any](capacity int, opts ...queue.Option) *ConcurrentQueue[T] {
+	return &ConcurrentQueue[T]{
+		q: queue.NewWithCapacity[T](capacity, opts...),
+	}
 }
 
-// New creates a new ConcurrentQueue.
-func New[T any]() *ConcurrentQueue[T] {
+// NewBounded creates a new ConcurrentQueue that holds at most capacity elements. Enqueue blocks while it is full.
+func NewBounded[T any](capacity int, opts ...queue.Option) *ConcurrentQueue[T] {
+	if capacity < 1 {
+		panic("cqueue: bounded capacity must be positive")
+	}
 	return &ConcurrentQueue[T]{
-		q: queue.New[T](),
+		q:     queue.New[T](opts...),
+		bound: capacity,
 	}
 }
 
-// Enqueue adds an element to the rear of the queue.
+// Enqueue adds an element to the rear of the queue. If the queue is bounded and full, it blocks until there is room.
+// Like sending on a closed channel, it panics with ErrClosed if the queue is closed.
 func (cq *ConcurrentQueue[T]) Enqueue(value T) {
 	cq.rw.Lock()
 	defer cq.rw.Unlock()
-	cq.q.Enqueue(value)
+	if err := cq.waitForSpace(context.Background()); err != nil {
+		panic(err)
+	}
+	cq.enqueue(value)
 }
 
-// Dequeue removes and returns an element from the front of the queue.
+// EnqueueContext is like Enqueue but returns ctx.Err() if the context is done before there is room,
+// and ErrClosed instead of panicking if the queue is closed.
+func (cq *ConcurrentQueue[T]) EnqueueContext(ctx context.Context, value T) error {
+	cq.rw.Lock()
+	defer cq.rw.Unlock()
+	if err := cq.waitForSpace(ctx); err != nil {
+		return err
+	}
+	cq.enqueue(value)
+	return nil
+}
+
+// TryEnqueue adds an element to the rear of the queue without blocking. Returns ErrFull if a bounded queue is full
+// and ErrClosed if the queue is closed.
+func (cq *ConcurrentQueue[T]) TryEnqueue(value T) error {
+	cq.rw.Lock()
+	defer cq.rw.Unlock()
+	if cq.closed {
+		return ErrClosed
+	}
+	if cq.full() {
+		return ErrFull
+	}
+	cq.enqueue(value)
+	return nil
+}
+
+// EnqueueAll adds values to the rear of the queue in order, taking the lock once. If the queue is bounded, it
+// enqueues as many values as fit and blocks until there is room for the rest. Panics with ErrClosed if the queue is
+// closed.
+func (cq *ConcurrentQueue[T]) EnqueueAll(values ...T) {
+	cq.rw.Lock()
+	defer cq.rw.Unlock()
+	for len(values) > 0 {
+		if err := cq.waitForSpace(context.Background()); err != nil {
+			panic(err)
+		}
+		n := len(values)
+		if cq.bound > 0 {
+			n = min(n, cq.bound-cq.q.Size())
+		}
+		cq.detach()
+		cq.q.EnqueueAll(values[:n]...)
+		cq.waiters.SignalN(n)
+		values = values[n:]
+	}
+}
+
+// Dequeue removes and returns an element from the front of the queue. Returns ErrClosed once a closed queue is drained.
 func (cq *ConcurrentQueue[T]) Dequeue() (T, error) {
 	cq.rw.Lock()
 	defer cq.rw.Unlock()
-	return cq.q.Dequeue()
+	return cq.dequeue()
+}
+
+// DequeueN removes up to n elements from the front of the queue under a single lock and returns them in order.
+// Returns ErrClosed once a closed queue is drained, so a consumer can tell the end of the stream from an empty queue.
+func (cq *ConcurrentQueue[T]) DequeueN(n int) ([]T, error) {
+	cq.rw.Lock()
+	defer cq.rw.Unlock()
+	if cq.closed && cq.q.IsEmpty() {
+		return []T{}, ErrClosed
+	}
+	cq.detach()
+	result := cq.q.DequeueN(n)
+	cq.space.SignalN(len(result))
+	return result, nil
+}
+
+// DequeueInto removes elements from the front of the queue into dst under a single lock until either is
+// exhausted, and returns how many were removed. Returns ErrClosed once a closed queue is drained.
+func (cq *ConcurrentQueue[T]) DequeueInto(dst []T) (int, error) {
+	cq.rw.Lock()
+	defer cq.rw.Unlock()
+	if cq.closed && cq.q.IsEmpty() {
+		return 0, ErrClosed
+	}
+	cq.detach()
+	n := cq.q.DequeueInto(dst)
+	cq.space.SignalN(n)
+	return n, nil
+}
+
+// DequeueWait removes and returns an element from the front of the queue, waiting for an element to be
+// enqueued if the queue is empty. Returns ctx.Err() if the context is done first, and ErrClosed once a closed queue
+// is drained.
+func (cq *ConcurrentQueue[T]) DequeueWait(ctx context.Context) (T, error) {
+	cq.rw.Lock()
+	defer cq.rw.Unlock()
+	if err := cq.waitForElement(ctx); err != nil {
+		var zero T
+		return zero, err
+	}
+	return cq.dequeue()
+}
+
+// DequeueTimeout is like DequeueWait but gives up after d. Returns context.DeadlineExceeded if it times out.
+func (cq *ConcurrentQueue[T]) DequeueTimeout(d time.Duration) (T, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return cq.DequeueWait(ctx)
+}
+
+// Cap returns the number of elements the underlying queue.Queue holds before it has to grow. For a bounded queue,
+// see Bound instead.
+func (cq *ConcurrentQueue[T]) Cap() int {
+	cq.rw.RLock()
+	defer cq.rw.RUnlock()
+	return cq.q.Cap()
+}
+
+// Bound returns the maximum number of elements the queue holds, or 0 if it is unbounded.
+func (cq *ConcurrentQueue[T]) Bound() int {
+	return cq.bound
+}
+
+// Close stops the queue from accepting new elements. Elements already in the queue can still be dequeued, after
+// which reads return ErrClosed. Goroutines blocked waiting on the queue are woken. Closing twice is a no-op.
+func (cq *ConcurrentQueue[T]) Close() {
+	cq.rw.Lock()
+	defer cq.rw.Unlock()
+	cq.closed = true
+	cq.waiters.Broadcast()
+	cq.space.Broadcast()
+}
+
+// IsClosed checks if the queue has been closed.
+func (cq *ConcurrentQueue[T]) IsClosed() bool {
+	cq.rw.RLock()
+	defer cq.rw.RUnlock()
+	return cq.closed
+}
+
+// IsFull checks if a bounded queue has no room left. An unbounded queue is never full.
+func (cq *ConcurrentQueue[T]) IsFull() bool {
+	cq.rw.RLock()
+	defer cq.rw.RUnlock()
+	return cq.full()
+}
+
+// enqueue adds value and wakes one goroutine waiting for an element. Must be called with cq.rw held.
+func (cq *ConcurrentQueue[T]) enqueue(value T) {
+	cq.detach()
+	cq.q.Enqueue(value)
+	cq.waiters.Signal()
+}
+
+// dequeue removes the front element and wakes one goroutine waiting for room. Must be called with cq.rw held.
+func (cq *ConcurrentQueue[T]) dequeue() (T, error) {
+	if cq.closed && cq.q.IsEmpty() {
+		var zero T
+		return zero, ErrClosed
+	}
+	cq.detach()
+	value, err := cq.q.Dequeue()
+	if err == nil {
+		cq.space.Signal()
+	}
+	return value, err
+}
+
+func (cq *ConcurrentQueue[T]) full() bool {
+	return cq.bound > 0 && cq.q.Size() >= cq.bound
+}
+
+// waitForElement parks until the queue is non-empty, closed or ctx is done. Must be called with cq.rw held, which is
+// released while parked and held again on return.
+func (cq *ConcurrentQueue[T]) waitForElement(ctx context.Context) error {
+	for cq.q.IsEmpty() && !cq.closed {
+		if err := cq.waiters.Wait(ctx, &cq.rw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForSpace parks until a bounded queue has room or ctx is done, and returns ErrClosed if the queue is closed.
+// Same locking rules as waitForElement.
+func (cq *ConcurrentQueue[T]) waitForSpace(ctx context.Context) error {
+	for {
+		if cq.closed {
+			return ErrClosed
+		}
+		if !cq.full() {
+			return nil
+		}
+		if err := cq.space.Wait(ctx, &cq.rw); err != nil {
+			return err
+		}
+	}
 }
 
 // Front returns the front element of the queue without removing it.
@@ -64,7 +293,8 @@ func (cq *ConcurrentQueue[T]) IsEmpty() bool {
 func (cq *ConcurrentQueue[T]) Clear() {
 	cq.rw.Lock()
 	defer cq.rw.Unlock()
-	cq.q.Clear()
+	cq.reset()
+	cq.space.Broadcast()
 }
 
 // ToSlice converts the queue to a slice and returns it.
@@ -73,3 +303,39 @@ func (cq *ConcurrentQueue[T]) ToSlice() []T {
 	defer cq.rw.RUnlock()
 	return cq.q.ToSlice()
 }
+
+// All returns an iterator over a snapshot of the queue from front to rear. The snapshot is taken under
+// the read lock when iteration starts, so the loop body may safely modify the queue.
+func (cq *ConcurrentQueue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range cq.ToSlice() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over a snapshot of the queue from rear to front.
+func (cq *ConcurrentQueue[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		values := cq.ToSlice()
+		for i := len(values) - 1; i >= 0; i-- {
+			if !yield(values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns an iterator over the positions and elements of a snapshot of the queue, where position 0 is
+// the front.
+func (cq *ConcurrentQueue[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, value := range cq.ToSlice() {
+			if !yield(i, value) {
+				return
+			}
+		}
+	}
+}
@@ -1 +1,630 @@
 package cqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/Shreyas-Adireddy/data_structures/internal/fakeclock"
+	"github.com/Shreyas-Adireddy/data_structures/queue"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentQueue(t *testing.T) {
+	cq := New[int]()
+	for i := 0; i < 5; i++ {
+		cq.Enqueue(i)
+	}
+	if cq.Size() != 5 {
+		t.Errorf("Expected size to be 5, got %d", cq.Size())
+	}
+	front, err := cq.Front()
+	if err != nil || front != 0 {
+		t.Errorf("Front returned unexpected value: %v, expected: %v, error: %v", front, 0, err)
+	}
+	back, err := cq.Back()
+	if err != nil || back != 4 {
+		t.Errorf("Back returned unexpected value: %v, expected: %v, error: %v", back, 4, err)
+	}
+	for i := 0; i < 5; i++ {
+		val, err := cq.Dequeue()
+		if err != nil || val != i {
+			t.Errorf("Dequeue returned unexpected value: %v, expected: %v, error: %v", val, i, err)
+		}
+	}
+	if _, err := cq.Dequeue(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+	if _, err := cq.Front(); !errors.Is(err, queue.ErrEmpty) {
+		t.Errorf("Expected queue.ErrEmpty, got %v", err)
+	}
+}
+
+func TestDequeueWait(t *testing.T) {
+	t.Run("Producer and consumers", func(t *testing.T) {
+		cq := New[int]()
+		const consumers, perConsumer = 4, 250
+		results := make(chan int, consumers*perConsumer)
+		wg := sync.WaitGroup{}
+		for c := 0; c < consumers; c++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < perConsumer; i++ {
+					val, err := cq.DequeueTimeout(time.Second)
+					if err != nil {
+						t.Errorf("Unexpected error: %v", err)
+						return
+					}
+					results <- val
+				}
+			}()
+		}
+		for i := 0; i < consumers*perConsumer; i++ {
+			cq.Enqueue(i)
+		}
+		wg.Wait()
+		close(results)
+
+		seen := make(map[int]bool)
+		for val := range results {
+			if seen[val] {
+				t.Errorf("Value %d was dequeued twice", val)
+			}
+			seen[val] = true
+		}
+		if len(seen) != consumers*perConsumer {
+			t.Errorf("Expected %d values, got %d", consumers*perConsumer, len(seen))
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		cq := New[int]()
+		_, err := cq.DequeueTimeout(5 * time.Millisecond)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		cq := New[int]()
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			_, err := cq.DequeueWait(ctx)
+			done <- err
+		}()
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+
+		// The cancelled waiter must not swallow a later element
+		cq.Enqueue(1)
+		if val, err := cq.DequeueTimeout(time.Second); err != nil || val != 1 {
+			t.Errorf("DequeueTimeout returned unexpected value: %v, error: %v", val, err)
+		}
+	})
+}
+
+func TestBoundedQueue(t *testing.T) {
+	t.Run("TryEnqueue", func(t *testing.T) {
+		cq := NewBounded[int](2)
+		if err := cq.TryEnqueue(1); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if err := cq.TryEnqueue(2); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if !cq.IsFull() {
+			t.Error("Expected queue to be full")
+		}
+		if err := cq.TryEnqueue(3); !errors.Is(err, ErrFull) {
+			t.Errorf("Expected ErrFull, got %v", err)
+		}
+		_, _ = cq.Dequeue()
+		if err := cq.TryEnqueue(3); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("EnqueueContext", func(t *testing.T) {
+		cq := NewBounded[int](1)
+		cq.Enqueue(1)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		if err := cq.EnqueueContext(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+		if cq.Size() != 1 {
+			t.Errorf("Expected size to be 1, got %d", cq.Size())
+		}
+	})
+
+	t.Run("Backpressure", func(t *testing.T) {
+		cq := NewBounded[int](4)
+		wg := sync.WaitGroup{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				cq.Enqueue(i)
+				if size := cq.Size(); size > 4 {
+					t.Errorf("Expected size to be at most 4, got %d", size)
+				}
+			}
+		}()
+		for i := 0; i < 1000; i++ {
+			val, err := cq.DequeueTimeout(time.Second)
+			if err != nil || val != i {
+				t.Errorf("DequeueTimeout returned unexpected value: %v, expected: %v, error: %v", val, i, err)
+			}
+		}
+		wg.Wait()
+	})
+}
+
+func TestClose(t *testing.T) {
+	t.Run("Drain", func(t *testing.T) {
+		cq := New[int]()
+		cq.Enqueue(1)
+		cq.Enqueue(2)
+		cq.Close()
+		if !cq.IsClosed() {
+			t.Error("Expected queue to be closed")
+		}
+		if err := cq.TryEnqueue(3); !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected ErrClosed, got %v", err)
+		}
+		for i := 1; i <= 2; i++ {
+			val, err := cq.DequeueTimeout(time.Second)
+			if err != nil || val != i {
+				t.Errorf("DequeueTimeout returned unexpected value: %v, expected: %v, error: %v", val, i, err)
+			}
+		}
+		if _, err := cq.Dequeue(); !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected ErrClosed, got %v", err)
+		}
+	})
+
+	t.Run("Enqueue panics", func(t *testing.T) {
+		cq := New[int]()
+		cq.Close()
+		defer func() {
+			if err, _ := recover().(error); !errors.Is(err, ErrClosed) {
+				t.Errorf("Expected panic with ErrClosed, got %v", err)
+			}
+		}()
+		cq.Enqueue(1)
+	})
+
+	t.Run("Wakes waiters", func(t *testing.T) {
+		cq := NewBounded[int](1)
+		cq.Enqueue(1)
+		producer := make(chan error)
+		go func() {
+			producer <- cq.EnqueueContext(context.Background(), 2)
+		}()
+		consumers := make(chan error)
+		empty := New[int]()
+		for i := 0; i < 3; i++ {
+			go func() {
+				_, err := empty.DequeueWait(context.Background())
+				consumers <- err
+			}()
+		}
+		time.Sleep(5 * time.Millisecond)
+		cq.Close()
+		empty.Close()
+		if err := <-producer; !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected ErrClosed, got %v", err)
+		}
+		for i := 0; i < 3; i++ {
+			if err := <-consumers; !errors.Is(err, ErrClosed) {
+				t.Errorf("Expected ErrClosed, got %v", err)
+			}
+		}
+	})
+}
+
+func TestIterators(t *testing.T) {
+	cq := New[int]()
+	for i := 0; i < 3; i++ {
+		cq.Enqueue(i)
+	}
+	// The loop body can write to the queue since it iterates over a snapshot
+	var got []int
+	for val := range cq.All() {
+		cq.Enqueue(val + 3)
+		got = append(got, val)
+	}
+	if expected := []int{0, 1, 2}; !slices.Equal(got, expected) {
+		t.Errorf("All returned %v, expected %v", got, expected)
+	}
+	if got, expected := slices.Collect(cq.Backward()), []int{5, 4, 3, 2, 1, 0}; !slices.Equal(got, expected) {
+		t.Errorf("Backward returned %v, expected %v", got, expected)
+	}
+	for i, val := range cq.Enumerate() {
+		if val != i {
+			t.Errorf("Enumerate returned %v at %v, expected %v", val, i, i)
+		}
+	}
+}
+
+func TestBatchOperations(t *testing.T) {
+	cq := NewBounded[int](4)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cq.EnqueueAll(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	}()
+
+	var got []int
+	dst := make([]int, 3)
+	for len(got) < 10 {
+		if cq.Size() > 4 {
+			t.Errorf("Expected size to be at most 4, got %d", cq.Size())
+		}
+		val, err := cq.DequeueTimeout(time.Second)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		got = append(got, val)
+		n, _ := cq.DequeueInto(dst)
+		got = append(got, dst[:n]...)
+	}
+	<-done
+	if expected := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}; !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	cq.EnqueueAll(1, 2)
+	if got, err := cq.DequeueN(5); err != nil || !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("DequeueN returned %v, expected %v (error: %v)", got, []int{1, 2}, err)
+	}
+
+	// The batch methods report the end of the stream once a closed queue is drained
+	cq.EnqueueAll(3)
+	cq.Close()
+	if got, err := cq.DequeueN(5); err != nil || !slices.Equal(got, []int{3}) {
+		t.Errorf("DequeueN returned %v, expected %v (error: %v)", got, []int{3}, err)
+	}
+	if _, err := cq.DequeueN(5); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected DequeueN to return ErrClosed, got %v", err)
+	}
+	if _, err := cq.DequeueInto(dst); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected DequeueInto to return ErrClosed, got %v", err)
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	cq := New[int]()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			cq.Enqueue(i)
+		}
+	}()
+	// Encoding takes a snapshot under the read lock, so it can run alongside writers
+	for i := 0; i < 10; i++ {
+		if _, err := json.Marshal(cq); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	wg.Wait()
+
+	data, err := cq.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var decoded ConcurrentQueue[int]
+	if err := decoded.UnmarshalBinary(data); err != nil || !slices.Equal(decoded.ToSlice(), cq.ToSlice()) {
+		t.Errorf("Expected UnmarshalBinary to restore the queue, got %v (error: %v)", decoded.ToSlice(), err)
+	}
+
+	// Decoding wakes goroutines waiting for an element
+	empty := New[int]()
+	result := make(chan int)
+	go func() {
+		val, _ := empty.DequeueWait(context.Background())
+		result <- val
+	}()
+	time.Sleep(5 * time.Millisecond)
+	if err := json.Unmarshal([]byte("[7]"), empty); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if val := <-result; val != 7 {
+		t.Errorf("Expected DequeueWait to return 7, got %v", val)
+	}
+
+	if err := json.Unmarshal([]byte("[1,2,3]"), NewBounded[int](2)); !errors.Is(err, ErrFull) {
+		t.Errorf("Expected ErrFull, got %v", err)
+	}
+	closed := New[int]()
+	closed.Close()
+	if err := json.Unmarshal([]byte("[1]"), closed); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestReliableQueue(t *testing.T) {
+	rq := NewReliable[string](0)
+	rq.Enqueue("a")
+	rq.Enqueue("b")
+
+	val, token, err := rq.DequeueLease(context.Background(), time.Minute)
+	if err != nil || val != "a" {
+		t.Fatalf("Expected DequeueLease to return a, got %v (error: %v)", val, err)
+	}
+	if rq.InFlight() != 1 || rq.Size() != 1 {
+		t.Errorf("Expected 1 element in flight and 1 waiting, got %d and %d", rq.InFlight(), rq.Size())
+	}
+	if err := rq.Ack(token); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := rq.Ack(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken when acknowledging twice, got %v", err)
+	}
+
+	// A rejected element is redelivered at the front
+	rq.Enqueue("c")
+	_, token, _ = rq.DequeueLease(context.Background(), time.Minute)
+	if err := rq.Nack(token); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if val, _, _ := rq.DequeueLease(context.Background(), time.Minute); val != "b" {
+		t.Errorf("Expected the rejected element b to be redelivered first, got %v", val)
+	}
+}
+
+func TestReliableQueueExpiry(t *testing.T) {
+	clock := fakeclock.New()
+	rq := NewReliable[int](0, WithClock(clock))
+	rq.Enqueue(1)
+	rq.Enqueue(2)
+	_, token, _ := rq.DequeueLease(context.Background(), time.Second)
+
+	// The lease expires, so the element reappears at the front ahead of 2
+	clock.Advance(time.Second)
+	waitFor(t, func() bool { return rq.InFlight() == 0 })
+	if err := rq.Ack(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken after the lease expired, got %v", err)
+	}
+	if val, _, _ := rq.DequeueLease(context.Background(), time.Minute); val != 1 {
+		t.Errorf("Expected the expired element 1 to be redelivered, got %v", val)
+	}
+
+	// A lease that is acknowledged in time doesn't expire
+	_, token, _ = rq.DequeueLease(context.Background(), time.Second)
+	clock.Advance(time.Second - time.Nanosecond)
+	if err := rq.Ack(token); err != nil {
+		t.Errorf("Unexpected error acknowledging before the timeout: %v", err)
+	}
+
+	// A blocked consumer is woken by a redelivery
+	empty := NewReliable[int](0, WithClock(clock))
+	empty.Enqueue(3)
+	empty.DequeueLease(context.Background(), time.Second)
+	result := make(chan int)
+	go func() {
+		val, _, _ := empty.DequeueLease(context.Background(), time.Minute)
+		result <- val
+	}()
+	clock.Advance(time.Second)
+	if val := <-result; val != 3 {
+		t.Errorf("Expected DequeueLease to wait for the redelivery of 3, got %v", val)
+	}
+}
+
+// waitFor polls cond until it holds, for state that changes on another goroutine
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReliableQueueDeadLetters(t *testing.T) {
+	rq := NewReliable[string](3)
+	rq.Enqueue("poison")
+	rq.Enqueue("ok")
+	for i := 0; i < 3; i++ {
+		val, token, _ := rq.DequeueLease(context.Background(), time.Minute)
+		if val != "poison" {
+			t.Fatalf("Expected delivery %d to be poison, got %v", i+1, val)
+		}
+		rq.Nack(token)
+	}
+	if letters := rq.DeadLetters(); !slices.Equal(letters, []string{"poison"}) {
+		t.Errorf("Expected [poison] in the dead-letter queue, got %v", letters)
+	}
+	if val, _, _ := rq.DequeueLease(context.Background(), time.Minute); val != "ok" {
+		t.Errorf("Expected ok to be delivered after poison was dead-lettered, got %v", val)
+	}
+
+	// Dead letters can still be drained once the queue is closed
+	rq.Close()
+	if val, err := rq.DequeueDeadLetter(); err != nil || val != "poison" {
+		t.Errorf("Expected DequeueDeadLetter to return poison, got %v (error: %v)", val, err)
+	}
+	if _, err := rq.DequeueDeadLetter(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty once the dead letters are drained, got %v", err)
+	}
+}
+
+func TestReliableQueueClose(t *testing.T) {
+	rq := NewReliable[int](0)
+	rq.Enqueue(1)
+	_, token, _ := rq.DequeueLease(context.Background(), time.Minute)
+	rq.Close()
+
+	// The consumer keeps waiting while an element is in flight, since it may still be redelivered
+	result := make(chan error)
+	go func() {
+		_, _, err := rq.DequeueLease(context.Background(), time.Minute)
+		result <- err
+	}()
+	rq.Ack(token)
+	if err := <-result; !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed once drained, got %v", err)
+	}
+
+	defer func() {
+		if r := recover(); r != ErrClosed {
+			t.Errorf("Expected Enqueue to panic with ErrClosed, got %v", r)
+		}
+	}()
+	rq.Enqueue(2)
+}
+
+// Every element is acknowledged exactly once even though consumers drop some leases
+func TestReliableQueueConcurrent(t *testing.T) {
+	clock := fakeclock.New()
+	rq := NewReliable[int](0, WithClock(clock))
+	const n = 200
+	for i := 0; i < n; i++ {
+		rq.Enqueue(i)
+	}
+	rq.Close()
+
+	var mu sync.Mutex
+	acked := make(map[int]int)
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				val, token, err := rq.DequeueLease(context.Background(), time.Second)
+				if err != nil {
+					return
+				}
+				switch i % 5 {
+				case 0:
+					// Crash without settling the lease, so it expires
+				case 1:
+					rq.Nack(token)
+				default:
+					if rq.Ack(token) == nil {
+						mu.Lock()
+						acked[val]++
+						mu.Unlock()
+					}
+				}
+			}
+		}(g)
+	}
+	// Keep time moving so dropped leases expire, until every consumer is done
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+			clock.Advance(time.Second)
+			runtime.Gosched()
+		}
+	}
+	if len(acked) != n {
+		t.Errorf("Expected all %d elements to be acknowledged, got %d", n, len(acked))
+	}
+	for val, count := range acked {
+		if count != 1 {
+			t.Errorf("Expected %d to be acknowledged once, got %d", val, count)
+		}
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	cq := New[int]()
+	cq.EnqueueAll(1, 2, 3, 4)
+	cq.Dequeue()
+	snap := cq.Snapshot()
+
+	// Writes after the snapshot don't show up in it
+	cq.Enqueue(5)
+	cq.Dequeue()
+	cq.Clear()
+	if got := snap.ToSlice(); !slices.Equal(got, []int{2, 3, 4}) {
+		t.Errorf("Expected the snapshot to hold [2 3 4], got %v", got)
+	}
+	if front, _ := snap.Front(); front != 2 {
+		t.Errorf("Expected Front to return 2, got %v", front)
+	}
+	if back, _ := snap.Back(); back != 4 {
+		t.Errorf("Expected Back to return 4, got %v", back)
+	}
+	if got := slices.Collect(snap.Backward()); !slices.Equal(got, []int{4, 3, 2}) {
+		t.Errorf("Expected Backward to yield [4 3 2], got %v", got)
+	}
+	if !cq.IsEmpty() {
+		t.Errorf("Expected queue to be empty, got %v", cq.ToSlice())
+	}
+
+	// Taking a snapshot doesn't copy, and only the first write after it does
+	cq.EnqueueAll(1, 2, 3)
+	if allocs := testing.AllocsPerRun(100, func() { cq.Snapshot() }); allocs > 1 {
+		t.Errorf("Expected Snapshot to allocate at most once, got %v", allocs)
+	}
+	cq.Snapshot()
+	cq.Enqueue(4)
+	if allocs := testing.AllocsPerRun(100, func() {
+		cq.Enqueue(5)
+		cq.Dequeue()
+	}); allocs != 0 {
+		t.Errorf("Expected writes after the first to not copy, got %v allocations", allocs)
+	}
+
+	// Clearing right after a snapshot swaps in a new array rather than emptying the shared one
+	expected := cq.ToSlice()
+	snap = cq.Snapshot()
+	cq.Clear()
+	if got := snap.ToSlice(); !slices.Equal(got, expected) {
+		t.Errorf("Expected the snapshot to hold %v after Clear, got %v", expected, got)
+	}
+	cq.Enqueue(6)
+	if got := cq.ToSlice(); !slices.Equal(got, []int{6}) {
+		t.Errorf("Expected [6], got %v", got)
+	}
+}
+
+func TestSnapshotConcurrent(t *testing.T) {
+	cq := New[int]()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			cq.Enqueue(i)
+			if i%3 == 0 {
+				cq.Dequeue()
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		snap := cq.Snapshot()
+		// A snapshot is consistent: consecutive elements in the order they were enqueued
+		prev := -1
+		for v := range snap.All() {
+			if prev != -1 && v != prev+1 {
+				t.Fatalf("Expected consecutive elements in the snapshot, got %d after %d", v, prev)
+			}
+			prev = v
+		}
+	}
+	wg.Wait()
+}
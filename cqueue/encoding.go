@@ -0,0 +1,57 @@
+package cqueue
+
+import (
+	"github.com/Shreyas-Adireddy/data_structures/internal/codec"
+	"github.com/Shreyas-Adireddy/data_structures/queue"
+)
+
+// MarshalJSON encodes a snapshot of the queue as a JSON array from front to rear, taken under the read lock.
+func (cq *ConcurrentQueue[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalJSON(cq.ToSlice())
+}
+
+// UnmarshalJSON replaces the contents of the queue with a JSON array, the first element becoming the front.
+func (cq *ConcurrentQueue[T]) UnmarshalJSON(data []byte) error {
+	return codec.UnmarshalJSON(data, cq.replace)
+}
+
+// GobEncode encodes a snapshot of the queue from front to rear, taken under the read lock.
+func (cq *ConcurrentQueue[T]) GobEncode() ([]byte, error) {
+	return codec.GobEncode(cq.ToSlice())
+}
+
+// GobDecode replaces the contents of the queue with data encoded by GobEncode.
+func (cq *ConcurrentQueue[T]) GobDecode(data []byte) error {
+	return codec.GobDecode(data, cq.replace)
+}
+
+// MarshalBinary encodes the queue with encoding/gob, same as GobEncode.
+func (cq *ConcurrentQueue[T]) MarshalBinary() ([]byte, error) {
+	return cq.GobEncode()
+}
+
+// UnmarshalBinary replaces the contents of the queue with data encoded by MarshalBinary.
+func (cq *ConcurrentQueue[T]) UnmarshalBinary(data []byte) error {
+	return cq.GobDecode(data)
+}
+
+// replace replaces the contents of the queue with values, the first element becoming the front. Works on a zero
+// ConcurrentQueue too. Returns ErrClosed if the queue is closed, or ErrFull if a bounded queue has no room for values.
+func (cq *ConcurrentQueue[T]) replace(values []T) error {
+	cq.rw.Lock()
+	defer cq.rw.Unlock()
+	if cq.closed {
+		return ErrClosed
+	}
+	if cq.bound > 0 && len(values) > cq.bound {
+		return ErrFull
+	}
+	if cq.q == nil {
+		cq.q = queue.New[T]()
+	}
+	cq.reset()
+	cq.q.EnqueueAll(values...)
+	cq.waiters.SignalN(len(values))
+	cq.space.Broadcast()
+	return nil
+}
@@ -0,0 +1,246 @@
+package cqueue
+
+import (
+	"context"
+	"errors"
+	"github.com/Shreyas-Adireddy/data_structures"
+	"github.com/Shreyas-Adireddy/data_structures/deque"
+	"github.com/Shreyas-Adireddy/data_structures/internal/notify"
+	"github.com/Shreyas-Adireddy/data_structures/queue"
+	"sync"
+	"time"
+)
+
+// ErrInvalidToken is returned by Ack and Nack when a lease was already settled or has expired.
+var ErrInvalidToken = errors.New("lease token is unknown or expired")
+
+// Token identifies a lease handed out by DequeueLease.
+type Token uint64
+
+type delivery[T any] struct {
+	value      T
+	deliveries int // Number of times the element has been leased
+}
+
+type lease[T any] struct {
+	delivery[T]
+	settled chan struct{} // Closed by Ack and Nack to stop waiting for the visibility timeout
+}
+
+// ReliableOption configures a ReliableQueue.
+type ReliableOption func(*reliableOptions)
+
+type reliableOptions struct {
+	clock datastructures.Clock
+}
+
+// WithClock sets the clock that visibility timeouts are measured with. Defaults to datastructures.SystemClock.
+func WithClock(clock datastructures.Clock) ReliableOption {
+	return func(o *reliableOptions) {
+		o.clock = clock
+	}
+}
+
+// ReliableQueue is a thread-safe queue with at-least-once delivery. DequeueLease hands out an element for a
+// visibility timeout, and the element goes back to the front of the queue unless it is acknowledged with Ack before
+// then. Elements that fail too many deliveries are moved to a dead-letter queue.
+type ReliableQueue[T any] struct {
+	ready         *deque.Deque[delivery[T]] // Elements waiting to be leased, redeliveries at the front
+	inFlight      map[Token]*lease[T]
+	rw            sync.RWMutex
+	nextToken     Token
+	maxDeliveries int // Deliveries after which an element is dead-lettered, 0 if unlimited
+	deadLetters   *queue.Queue[T]
+	clock         datastructures.Clock
+	closed        bool
+	waiters       notify.List // Goroutines blocked in DequeueLease, guarded by rw
+}
+
+// NewReliable creates a new ReliableQueue. An element whose lease expires or is rejected with Nack for the
+// maxDeliveries-th time goes to the dead-letter queue instead of being redelivered. A maxDeliveries of 0 redelivers
+// forever.
+func NewReliable[T any](maxDeliveries int, opts ...ReliableOption) *ReliableQueue[T] {
+	o := reliableOptions{clock: datastructures.SystemClock}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &ReliableQueue[T]{
+		ready:         deque.New[delivery[T]](),
+		inFlight:      make(map[Token]*lease[T]),
+		maxDeliveries: max(maxDeliveries, 0),
+		deadLetters:   queue.New[T](),
+		clock:         o.clock,
+	}
+}
+
+// Enqueue adds an element to the rear of the queue. Like sending on a closed channel, it panics with ErrClosed if
+// the queue is closed.
+func (rq *ReliableQueue[T]) Enqueue(value T) {
+	rq.rw.Lock()
+	defer rq.rw.Unlock()
+	if rq.closed {
+		panic(ErrClosed)
+	}
+	rq.ready.AddRear(delivery[T]{value: value})
+	rq.waiters.Signal()
+}
+
+// DequeueLease removes the front element and leases it for visibilityTimeout, waiting for an element if the queue is
+// empty. The element is redelivered unless the returned token is passed to Ack before the timeout. Returns ctx.Err()
+// if the context is done first, or ErrClosed once the queue is closed and every element has been acknowledged or
+// dead-lettered. Panics if visibilityTimeout isn't positive.
+func (rq *ReliableQueue[T]) DequeueLease(ctx context.Context, visibilityTimeout time.Duration) (T, Token, error) {
+	if visibilityTimeout <= 0 {
+		panic("cqueue: visibility timeout must be positive")
+	}
+	rq.rw.Lock()
+	defer rq.rw.Unlock()
+	for rq.ready.IsEmpty() {
+		if rq.drained() {
+			var zero T
+			return zero, 0, ErrClosed
+		}
+		if err := rq.waiters.Wait(ctx, &rq.rw); err != nil {
+			var zero T
+			return zero, 0, err
+		}
+	}
+	d, _ := rq.ready.PopFront()
+	d.deliveries++
+	rq.nextToken++
+	token := rq.nextToken
+	l := &lease[T]{delivery: d, settled: make(chan struct{})}
+	rq.inFlight[token] = l
+	expired := rq.clock.After(visibilityTimeout)
+	go func() {
+		select {
+		case <-expired:
+			rq.expire(token)
+		case <-l.settled:
+		}
+	}()
+	return d.value, token, nil
+}
+
+// Ack acknowledges that the element leased under token was handled, so it is never redelivered.
+func (rq *ReliableQueue[T]) Ack(token Token) error {
+	rq.rw.Lock()
+	defer rq.rw.Unlock()
+	l, ok := rq.settle(token)
+	if !ok {
+		return ErrInvalidToken
+	}
+	close(l.settled)
+	rq.wakeIfDrained()
+	return nil
+}
+
+// Nack rejects the element leased under token, so it is redelivered at the front of the queue straight away, or
+// dead-lettered if it has run out of deliveries.
+func (rq *ReliableQueue[T]) Nack(token Token) error {
+	rq.rw.Lock()
+	defer rq.rw.Unlock()
+	l, ok := rq.settle(token)
+	if !ok {
+		return ErrInvalidToken
+	}
+	close(l.settled)
+	rq.redeliver(l.delivery)
+	return nil
+}
+
+// expire redelivers the element leased under token if it is still in flight once its visibility timeout passes.
+func (rq *ReliableQueue[T]) expire(token Token) {
+	rq.rw.Lock()
+	defer rq.rw.Unlock()
+	if l, ok := rq.settle(token); ok {
+		rq.redeliver(l.delivery)
+	}
+}
+
+// settle removes a lease from the in-flight set. Must be called with rq.rw held.
+func (rq *ReliableQueue[T]) settle(token Token) (*lease[T], bool) {
+	l, ok := rq.inFlight[token]
+	if ok {
+		delete(rq.inFlight, token)
+	}
+	return l, ok
+}
+
+// redeliver puts d back at the front of the queue, or moves it to the dead-letter queue once it has used up its
+// deliveries. Must be called with rq.rw held.
+func (rq *ReliableQueue[T]) redeliver(d delivery[T]) {
+	if rq.maxDeliveries > 0 && d.deliveries >= rq.maxDeliveries {
+		rq.deadLetters.Enqueue(d.value)
+		rq.wakeIfDrained()
+		return
+	}
+	rq.ready.AddFront(d)
+	rq.waiters.Signal()
+}
+
+// drained reports whether the queue is closed with nothing left to lease or redeliver. Must be called with rq.rw held.
+func (rq *ReliableQueue[T]) drained() bool {
+	return rq.closed && rq.ready.IsEmpty() && len(rq.inFlight) == 0
+}
+
+// wakeIfDrained wakes every waiter once the queue is drained, so they return ErrClosed. Must be called with rq.rw
+// held.
+func (rq *ReliableQueue[T]) wakeIfDrained() {
+	if rq.drained() {
+		rq.waiters.Broadcast()
+	}
+}
+
+// DeadLetters returns a copy of the elements that used up their deliveries, oldest first.
+func (rq *ReliableQueue[T]) DeadLetters() []T {
+	rq.rw.RLock()
+	defer rq.rw.RUnlock()
+	return rq.deadLetters.ToSlice()
+}
+
+// DequeueDeadLetter removes and returns the oldest element that used up its deliveries. Returns ErrEmpty if there
+// is none. It keeps working after the queue is closed, so dead letters can always be drained.
+func (rq *ReliableQueue[T]) DequeueDeadLetter() (T, error) {
+	rq.rw.Lock()
+	defer rq.rw.Unlock()
+	return rq.deadLetters.Dequeue()
+}
+
+// Close stops the queue from accepting new elements. Elements already in the queue, including leased ones that are
+// redelivered, can still be leased. Once every element is acknowledged or dead-lettered, DequeueLease returns
+// ErrClosed. Closing twice is a no-op.
+func (rq *ReliableQueue[T]) Close() {
+	rq.rw.Lock()
+	defer rq.rw.Unlock()
+	rq.closed = true
+	rq.wakeIfDrained()
+}
+
+// IsClosed checks if the queue has been closed.
+func (rq *ReliableQueue[T]) IsClosed() bool {
+	rq.rw.RLock()
+	defer rq.rw.RUnlock()
+	return rq.closed
+}
+
+// Size returns the number of elements waiting to be leased.
+func (rq *ReliableQueue[T]) Size() int {
+	rq.rw.RLock()
+	defer rq.rw.RUnlock()
+	return rq.ready.Size()
+}
+
+// InFlight returns the number of leased elements that have been neither acknowledged nor redelivered.
+func (rq *ReliableQueue[T]) InFlight() int {
+	rq.rw.RLock()
+	defer rq.rw.RUnlock()
+	return len(rq.inFlight)
+}
+
+// IsEmpty checks if no element is waiting to be leased.
+func (rq *ReliableQueue[T]) IsEmpty() bool {
+	rq.rw.RLock()
+	defer rq.rw.RUnlock()
+	return rq.ready.IsEmpty()
+}
@@ -0,0 +1,84 @@
+package cqueue
+
+import (
+	"github.com/Shreyas-Adireddy/data_structures/queue"
+	"iter"
+)
+
+// Snapshot is a read-only view of a ConcurrentQueue as it was when Snapshot was called. It shares the queue's
+// backing array until the queue is next written to, and reading it takes no lock. Writers pay for the copy instead:
+// the first write after Snapshot copies the array in O(n) while holding the write lock.
+type Snapshot[T any] struct {
+	q *queue.Queue[T]
+}
+
+// Snapshot returns a read-only view of the queue in O(1). Instead of holding the read lock while the contents are
+// copied like ToSlice does, the queue copies its backing array on the first write after a snapshot, under the write
+// lock. Clear doesn't need the old contents, so it allocates a new array instead.
+func (cq *ConcurrentQueue[T]) Snapshot() *Snapshot[T] {
+	cq.rw.RLock()
+	defer cq.rw.RUnlock()
+	cq.shared.Store(true)
+	return &Snapshot[T]{q: cq.q}
+}
+
+// detach gives the queue its own copy of the backing array if a Snapshot refers to it. Must be called with cq.rw
+// held for writing, before modifying cq.q.
+func (cq *ConcurrentQueue[T]) detach() {
+	if cq.shared.Load() {
+		cq.q = cq.q.Clone()
+		cq.shared.Store(false)
+	}
+}
+
+// reset empties the queue. Must be called with cq.rw held for writing. Unlike detach followed by Clear it doesn't
+// copy contents a Snapshot refers to only to throw them away.
+func (cq *ConcurrentQueue[T]) reset() {
+	if cq.shared.Load() {
+		// Clear allocates a new backing array, so emptying a copy of the struct leaves the snapshot's alone
+		q := *cq.q
+		cq.q = &q
+		cq.shared.Store(false)
+	}
+	cq.q.Clear()
+}
+
+// Front returns the front element of the snapshot.
+func (s *Snapshot[T]) Front() (T, error) {
+	return s.q.Front()
+}
+
+// Back returns the rear element of the snapshot.
+func (s *Snapshot[T]) Back() (T, error) {
+	return s.q.Back()
+}
+
+// Size returns the number of elements in the snapshot.
+func (s *Snapshot[T]) Size() int {
+	return s.q.Size()
+}
+
+// IsEmpty checks if the snapshot is empty.
+func (s *Snapshot[T]) IsEmpty() bool {
+	return s.q.IsEmpty()
+}
+
+// ToSlice returns the elements of the snapshot from front to rear.
+func (s *Snapshot[T]) ToSlice() []T {
+	return s.q.ToSlice()
+}
+
+// All returns an iterator over the elements of the snapshot from front to rear, without copying them.
+func (s *Snapshot[T]) All() iter.Seq[T] {
+	return s.q.All()
+}
+
+// Backward returns an iterator over the elements of the snapshot from rear to front.
+func (s *Snapshot[T]) Backward() iter.Seq[T] {
+	return s.q.Backward()
+}
+
+// Enumerate returns an iterator over the positions and elements of the snapshot, where position 0 is the front.
+func (s *Snapshot[T]) Enumerate() iter.Seq2[int, T] {
+	return s.q.Enumerate()
+}
@@ -1,13 +1,30 @@
 package cstack
 
 import (
-	"data_structures/stack"
+	"context"
+	"github.com/Shreyas-Adireddy/data_structures"
+	"github.com/Shreyas-Adireddy/data_structures/internal/notify"
+	"github.com/Shreyas-Adireddy/data_structures/stack"
+	"iter"
 	"sync"
+	"time"
 )
 
+var (
+	// ErrEmpty is returned by Pop and Peek when the stack has no elements.
+	ErrEmpty = datastructures.ErrEmpty
+	// ErrClosed is returned when popping from a closed and drained stack, and is the panic value when pushing to a
+	// closed stack.
+	ErrClosed = datastructures.ErrClosed
+)
+
+var _ datastructures.LIFO[int] = (*ConcurrentStack[int])(nil)
+
 type ConcurrentStack[T any] struct {
-	stack *stack.Stack[T]
-	rw    sync.RWMutex // RWMutex for read/write lock
+	stack   *stack.Stack[T]
+	rw      sync.RWMutex // RWMutex for read/write lock
+	closed  bool
+	waiters notify.List // Goroutines blocked in PopWait/PopContext, guarded by rw
 }
 
 // New creates a new concurrent Stack.
@@ -17,17 +34,92 @@ func New[T any]() *ConcurrentStack[T] {
 	}
 }
 
-// Push adds an element to the stack.
+// NewWithCapacity creates a new concurrent Stack with room for capacity elements before it has to grow.
+func NewWithCapacity[T any](capacity int) *ConcurrentStack[T] {
+	return &ConcurrentStack[T]{
+		stack: stack.NewWithCapacity[T](capacity),
+	}
+}
+
+// Push adds an element to the stack. Like sending on a closed channel, it panics with ErrClosed if the stack is closed.
 func (cs *ConcurrentStack[T]) Push(element T) {
+	if err := cs.TryPush(element); err != nil {
+		panic(err)
+	}
+}
+
+// TryPush is like Push but returns ErrClosed instead of panicking if the stack is closed.
+func (cs *ConcurrentStack[T]) TryPush(element T) error {
 	cs.rw.Lock()
 	defer cs.rw.Unlock()
+	if cs.closed {
+		return ErrClosed
+	}
 	cs.stack.Push(element)
+	cs.waiters.Signal()
+	return nil
+}
+
+// PushAll adds values to the stack in order under a single lock, so the last one ends up on top. Panics with
+// ErrClosed if the stack is closed.
+func (cs *ConcurrentStack[T]) PushAll(values ...T) {
+	cs.rw.Lock()
+	defer cs.rw.Unlock()
+	if cs.closed {
+		panic(ErrClosed)
+	}
+	cs.stack.PushAll(values...)
+	cs.waiters.SignalN(len(values))
 }
 
 // Pop removes and returns the top element of the stack. Blocks if it can't obtain the lock.
+// Returns ErrClosed once a closed stack is drained.
 func (cs *ConcurrentStack[T]) Pop() (T, error) {
 	cs.rw.Lock()
 	defer cs.rw.Unlock()
+	return cs.pop()
+}
+
+// PopN removes up to n elements from the top of the stack under a single lock and returns them, top first.
+// Returns ErrClosed once a closed stack is drained.
+func (cs *ConcurrentStack[T]) PopN(n int) ([]T, error) {
+	cs.rw.Lock()
+	defer cs.rw.Unlock()
+	if cs.closed && cs.stack.IsEmpty() {
+		return []T{}, ErrClosed
+	}
+	return cs.stack.PopN(n), nil
+}
+
+// PopWait removes and returns the top element of the stack, waiting up to timeout for an element to be
+// pushed if the stack is empty. Returns context.DeadlineExceeded if the timeout expires first.
+func (cs *ConcurrentStack[T]) PopWait(timeout time.Duration) (T, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return cs.PopContext(ctx)
+}
+
+// PopContext removes and returns the top element of the stack, waiting for an element to be pushed if the
+// stack is empty. Returns ctx.Err() if the context is done first, and ErrClosed once a closed stack is drained.
+func (cs *ConcurrentStack[T]) PopContext(ctx context.Context) (T, error) {
+	cs.rw.Lock()
+	defer cs.rw.Unlock()
+	for cs.stack.IsEmpty() && !cs.closed {
+		if err := cs.waiters.Wait(ctx, &cs.rw); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+	return cs.pop()
+}
+
+// pop removes the top element, reporting ErrClosed rather than an empty stack once closed. Must be called with
+// cs.rw held.
+func (cs *ConcurrentStack[T]) pop() (T, error) {
+	if cs.closed && cs.stack.IsEmpty() {
+		var zero T
+		return zero, ErrClosed
+	}
 	return cs.stack.Pop()
 }
 
@@ -52,9 +144,75 @@ func (cs *ConcurrentStack[T]) Size() int {
 	return cs.stack.Size()
 }
 
+// Cap returns the number of elements the stack holds before it has to grow.
+func (cs *ConcurrentStack[T]) Cap() int {
+	cs.rw.RLock()
+	defer cs.rw.RUnlock()
+	return cs.stack.Cap()
+}
+
 // Clear removes all elements from the stack
 func (cs *ConcurrentStack[T]) Clear() {
 	cs.rw.Lock()
 	defer cs.rw.Unlock()
 	cs.stack.Clear()
 }
+
+// ToSlice converts the stack to a slice and returns it, bottom first.
+func (cs *ConcurrentStack[T]) ToSlice() []T {
+	cs.rw.RLock()
+	defer cs.rw.RUnlock()
+	return cs.stack.ToSlice()
+}
+
+// All returns an iterator over a snapshot of the stack from bottom to top. The snapshot is taken under
+// the read lock when iteration starts, so the loop body may safely modify the stack.
+func (cs *ConcurrentStack[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range cs.ToSlice() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over a snapshot of the stack from top to bottom.
+func (cs *ConcurrentStack[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		values := cs.ToSlice()
+		for i := len(values) - 1; i >= 0; i-- {
+			if !yield(values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns an iterator over the positions and elements of a snapshot of the stack, where position 0 is
+// the bottom.
+func (cs *ConcurrentStack[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, value := range cs.ToSlice() {
+			if !yield(i, value) {
+				return
+			}
+		}
+	}
+}
+
+// Close stops the stack from accepting new elements. Elements already on the stack can still be popped, after
+// which pops return ErrClosed. Goroutines blocked in PopWait/PopContext are woken. Closing twice is a no-op.
+func (cs *ConcurrentStack[T]) Close() {
+	cs.rw.Lock()
+	defer cs.rw.Unlock()
+	cs.closed = true
+	cs.waiters.Broadcast()
+}
+
+// IsClosed checks if the stack has been closed.
+func (cs *ConcurrentStack[T]) IsClosed() bool {
+	cs.rw.RLock()
+	defer cs.rw.RUnlock()
+	return cs.closed
+}
@@ -1,7 +1,15 @@
 package cstack
 
 import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"github.com/Shreyas-Adireddy/data_structures"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -68,6 +76,72 @@ func TestConcurrentStack(t *testing.T) {
 		}
 	})
 
+	t.Run("PopWait timeout", func(t *testing.T) {
+		cs := New[int]()
+		_, err := cs.PopWait(5 * time.Millisecond)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+
+	t.Run("PopContext cancel", func(t *testing.T) {
+		cs := New[int]()
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			_, err := cs.PopContext(ctx)
+			done <- err
+		}()
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+
+		// A push after the waiter left must stay on the stack
+		cs.Push(1)
+		if cs.Size() != 1 {
+			t.Errorf("Expected size to be 1, but got %d", cs.Size())
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		cs := New[int]()
+		cs.Push(1)
+		done := make(chan error)
+		empty := New[int]()
+		go func() {
+			_, err := empty.PopContext(context.Background())
+			done <- err
+		}()
+		time.Sleep(5 * time.Millisecond)
+		cs.Close()
+		empty.Close()
+		if err := <-done; !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected ErrClosed, got %v", err)
+		}
+
+		if top, err := cs.Pop(); err != nil || top != 1 {
+			t.Errorf("Expected to drain 1 from closed stack, got %v (error: %v)", top, err)
+		}
+		if _, err := cs.Pop(); !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected ErrClosed, got %v", err)
+		}
+		if _, err := cs.PopN(2); !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected PopN to return ErrClosed, got %v", err)
+		}
+		if err := cs.TryPush(2); !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected TryPush to return ErrClosed, got %v", err)
+		}
+
+		defer func() {
+			if err, _ := recover().(error); !errors.Is(err, ErrClosed) {
+				t.Errorf("Expected panic with ErrClosed, got %v", err)
+			}
+		}()
+		cs.Push(2)
+	})
+
 	t.Run("Peek", func(t *testing.T) {
 		cs := New[int]()
 		cs.Push(1)
@@ -161,7 +235,153 @@ func TestStack(t *testing.T) {
 
 	// Test Pop when stack is empty
 	_, err = s.Pop()
-	if err == nil {
-		t.Error("Expected error when popping from empty stack, but got nil")
+	if !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty when popping from empty stack, got %v", err)
+	}
+}
+
+// Runs the same checks against every LIFO implementation
+func TestStackImplementations(t *testing.T) {
+	implementations := map[string]func() datastructures.LIFO[int]{
+		"ConcurrentStack":         func() datastructures.LIFO[int] { return New[int]() },
+		"TreiberStack":            func() datastructures.LIFO[int] { return NewTreiber[int]() },
+		"TreiberStackElimination": func() datastructures.LIFO[int] { return NewTreiberWithElimination[int](4) },
+	}
+	for name, newStack := range implementations {
+		t.Run(name, func(t *testing.T) {
+			s := newStack()
+			if _, err := s.Pop(); !errors.Is(err, ErrEmpty) {
+				t.Errorf("Expected ErrEmpty, got %v", err)
+			}
+			s.Push(1)
+			s.Push(2)
+			if top, err := s.Peek(); err != nil || top != 2 {
+				t.Errorf("Expected top element to be 2, got %v (error: %v)", top, err)
+			}
+
+			const goroutines, perGoroutine = 8, 1000
+			wg := sync.WaitGroup{}
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < perGoroutine; i++ {
+						s.Push(g*perGoroutine + i)
+						if _, err := s.Pop(); err != nil {
+							t.Errorf("Unexpected error: %v", err)
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+
+			if s.Size() != 2 {
+				t.Errorf("Expected size to be 2, got %d", s.Size())
+			}
+			popped := []int{}
+			for !s.IsEmpty() {
+				val, _ := s.Pop()
+				popped = append(popped, val)
+			}
+			if len(popped) != 2 {
+				t.Errorf("Expected 2 elements to be left, got %v", popped)
+			}
+		})
+	}
+}
+
+func TestTreiberStack(t *testing.T) {
+	ts := NewTreiberWithElimination[int](2)
+	ts.Push(1)
+	ts.Push(2)
+	ts.Push(3)
+	if expected := []int{1, 2, 3}; !slices.Equal(ts.ToSlice(), expected) {
+		t.Errorf("Expected %v, got %v", expected, ts.ToSlice())
+	}
+	ts.Clear()
+	if !ts.IsEmpty() || ts.Size() != 0 {
+		t.Errorf("Expected stack to be empty after Clear, got size %d", ts.Size())
+	}
+
+	// Every pushed value must be popped exactly once, whether it went through the stack or the elimination array
+	const goroutines, perGoroutine = 8, 2000
+	seen := make([]atomic.Bool, goroutines*perGoroutine)
+	wg := sync.WaitGroup{}
+	for g := 0; g < goroutines; g++ {
+		wg.Add(2)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				ts.Push(g*perGoroutine + i)
+			}
+		}(g)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; {
+				val, err := ts.Pop()
+				if err != nil {
+					continue
+				}
+				if seen[val].Swap(true) {
+					t.Errorf("Value %d was popped twice", val)
+				}
+				i++
+			}
+		}()
+	}
+	wg.Wait()
+	if !ts.IsEmpty() {
+		t.Errorf("Expected stack to be empty, got size %d", ts.Size())
+	}
+}
+
+func BenchmarkStacks(b *testing.B) {
+	implementations := map[string]func() datastructures.LIFO[int]{
+		"ConcurrentStack":         func() datastructures.LIFO[int] { return New[int]() },
+		"TreiberStack":            func() datastructures.LIFO[int] { return NewTreiber[int]() },
+		"TreiberStackElimination": func() datastructures.LIFO[int] { return NewTreiberWithElimination[int](8) },
+	}
+	for name, newStack := range implementations {
+		b.Run(name, func(b *testing.B) {
+			s := newStack()
+			b.SetParallelism(8)
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					s.Push(1)
+					_, _ = s.Pop()
+				}
+			})
+		})
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	cs := New[string]()
+	cs.PushAll("a", "b", "c")
+	data, err := json.Marshal(cs)
+	if err != nil || string(data) != `["a","b","c"]` {
+		t.Errorf(`Expected MarshalJSON to return ["a","b","c"], got %s (error: %v)`, data, err)
+	}
+
+	var fromJSON ConcurrentStack[string]
+	if err := json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if top, err := fromJSON.Pop(); err != nil || top != "c" {
+		t.Errorf("Expected Pop to return c, got %v (error: %v)", top, err)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(cs); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fromGob := New[string]()
+	if err := gob.NewDecoder(&buf).Decode(fromGob); err != nil || !slices.Equal(fromGob.ToSlice(), cs.ToSlice()) {
+		t.Errorf("Expected GobDecode to restore %v, got %v (error: %v)", cs.ToSlice(), fromGob.ToSlice(), err)
+	}
+
+	fromGob.Close()
+	if err := fromGob.UnmarshalJSON(data); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
 	}
 }
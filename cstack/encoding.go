@@ -0,0 +1,53 @@
+package cstack
+
+import (
+	"github.com/Shreyas-Adireddy/data_structures/internal/codec"
+	"github.com/Shreyas-Adireddy/data_structures/stack"
+)
+
+// MarshalJSON encodes a snapshot of the stack as a JSON array from bottom to top, taken under the read lock.
+func (cs *ConcurrentStack[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalJSON(cs.ToSlice())
+}
+
+// UnmarshalJSON replaces the contents of the stack with a JSON array, the last element ending up on top.
+func (cs *ConcurrentStack[T]) UnmarshalJSON(data []byte) error {
+	return codec.UnmarshalJSON(data, cs.replace)
+}
+
+// GobEncode encodes a snapshot of the stack from bottom to top, taken under the read lock.
+func (cs *ConcurrentStack[T]) GobEncode() ([]byte, error) {
+	return codec.GobEncode(cs.ToSlice())
+}
+
+// GobDecode replaces the contents of the stack with data encoded by GobEncode.
+func (cs *ConcurrentStack[T]) GobDecode(data []byte) error {
+	return codec.GobDecode(data, cs.replace)
+}
+
+// MarshalBinary encodes the stack with encoding/gob, same as GobEncode.
+func (cs *ConcurrentStack[T]) MarshalBinary() ([]byte, error) {
+	return cs.GobEncode()
+}
+
+// UnmarshalBinary replaces the contents of the stack with data encoded by MarshalBinary.
+func (cs *ConcurrentStack[T]) UnmarshalBinary(data []byte) error {
+	return cs.GobDecode(data)
+}
+
+// replace replaces the contents of the stack with values, the last element ending up on top. Works on a zero
+// ConcurrentStack too. Returns ErrClosed if the stack is closed.
+func (cs *ConcurrentStack[T]) replace(values []T) error {
+	cs.rw.Lock()
+	defer cs.rw.Unlock()
+	if cs.closed {
+		return ErrClosed
+	}
+	if cs.stack == nil {
+		cs.stack = stack.New[T]()
+	}
+	cs.stack.Clear()
+	cs.stack.PushAll(values...)
+	cs.waiters.SignalN(len(values))
+	return nil
+}
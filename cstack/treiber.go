@@ -0,0 +1,154 @@
+package cstack
+
+import (
+	"github.com/Shreyas-Adireddy/data_structures"
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+)
+
+var _ datastructures.LIFO[int] = (*TreiberStack[int])(nil)
+
+// eliminationSpins is how many times a Push offered to the elimination array yields before withdrawing the offer.
+const eliminationSpins = 16
+
+type node[T any] struct {
+	value T
+	next  *node[T] // Set before the node is published on the stack, never modified after
+}
+
+// TreiberStack is a lock-free stack. Push and Pop retry a compare-and-swap on the top pointer instead of taking a
+// lock, so they scale past one core.
+//
+// Every Push allocates a fresh node and nodes are never reused, so the garbage collector rules out the ABA problem:
+// a node can't be popped, freed and pushed back while another goroutine still holds a pointer to it.
+type TreiberStack[T any] struct {
+	top  atomic.Pointer[node[T]]
+	size atomic.Int64
+	// Elimination array, nil if disabled. A Push that loses a CAS race parks its node in a random slot for a moment
+	// so a Pop that also lost can take it directly, letting the pair cancel out without touching top.
+	slots []atomic.Pointer[node[T]]
+}
+
+// NewTreiber creates a new lock-free TreiberStack.
+func NewTreiber[T any]() *TreiberStack[T] {
+	return &TreiberStack[T]{}
+}
+
+// NewTreiberWithElimination creates a new lock-free TreiberStack with an elimination array of the given width,
+// which reduces contention on the top of the stack when many goroutines push and pop at once.
+func NewTreiberWithElimination[T any](width int) *TreiberStack[T] {
+	if width < 1 {
+		panic("cstack: elimination width must be positive")
+	}
+	return &TreiberStack[T]{
+		slots: make([]atomic.Pointer[node[T]], width),
+	}
+}
+
+// Push adds an element to the stack.
+func (ts *TreiberStack[T]) Push(element T) {
+	n := &node[T]{value: element}
+	for {
+		top := ts.top.Load()
+		n.next = top
+		if ts.top.CompareAndSwap(top, n) {
+			ts.size.Add(1)
+			return
+		}
+		if ts.offer(n) {
+			return
+		}
+	}
+}
+
+// Pop removes and returns the top element of the stack.
+func (ts *TreiberStack[T]) Pop() (T, error) {
+	for {
+		top := ts.top.Load()
+		if top == nil {
+			var zero T
+			return zero, ErrEmpty
+		}
+		if ts.top.CompareAndSwap(top, top.next) {
+			ts.size.Add(-1)
+			return top.value, nil
+		}
+		if n := ts.take(); n != nil {
+			return n.value, nil
+		}
+	}
+}
+
+// Peek returns the top element of the stack without removing it.
+func (ts *TreiberStack[T]) Peek() (T, error) {
+	top := ts.top.Load()
+	if top == nil {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return top.value, nil
+}
+
+// IsEmpty checks if the stack is empty.
+func (ts *TreiberStack[T]) IsEmpty() bool {
+	return ts.top.Load() == nil
+}
+
+// Size returns the number of elements in the stack. Under concurrent use it is only a snapshot.
+func (ts *TreiberStack[T]) Size() int {
+	// A Pop can briefly be counted before the Push it removed
+	return max(int(ts.size.Load()), 0)
+}
+
+// Clear removes all elements from the stack in one step.
+func (ts *TreiberStack[T]) Clear() {
+	removed := 0
+	for n := ts.top.Swap(nil); n != nil; n = n.next {
+		removed++
+	}
+	ts.size.Add(int64(-removed))
+}
+
+// ToSlice converts the stack to a slice and returns it, bottom first.
+func (ts *TreiberStack[T]) ToSlice() []T {
+	result := []T{}
+	for n := ts.top.Load(); n != nil; n = n.next {
+		result = append(result, n.value)
+	}
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result
+}
+
+// offer parks n in a random elimination slot and reports whether a Pop took it before the offer was withdrawn.
+func (ts *TreiberStack[T]) offer(n *node[T]) bool {
+	if ts.slots == nil {
+		return false
+	}
+	slot := &ts.slots[rand.IntN(len(ts.slots))]
+	if !slot.CompareAndSwap(nil, n) {
+		return false
+	}
+	for i := 0; i < eliminationSpins; i++ {
+		if slot.Load() != n {
+			return true
+		}
+		runtime.Gosched()
+	}
+	// If withdrawing fails, a Pop took n in the meantime
+	return !slot.CompareAndSwap(n, nil)
+}
+
+// take removes a node parked by a concurrent Push from a random elimination slot, or returns nil if there is none.
+func (ts *TreiberStack[T]) take() *node[T] {
+	if ts.slots == nil {
+		return nil
+	}
+	slot := &ts.slots[rand.IntN(len(ts.slots))]
+	if n := slot.Load(); n != nil && slot.CompareAndSwap(n, nil) {
+		return n
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+// Package delayqueue provides a concurrent queue whose elements only become available once their scheduled time
+// has passed.
+package delayqueue
+
+import (
+	"cmp"
+	"context"
+	"github.com/Shreyas-Adireddy/data_structures"
+	"github.com/Shreyas-Adireddy/data_structures/internal/notify"
+	"github.com/Shreyas-Adireddy/data_structures/pqueue"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrEmpty is returned by TryTake when no element is due yet.
+	ErrEmpty = datastructures.ErrEmpty
+	// ErrClosed is returned by Take and TryTake once the queue is closed and drained.
+	ErrClosed = datastructures.ErrClosed
+)
+
+var _ datastructures.Container[int] = (*DelayQueue[int])(nil)
+
+type Clock = datastructures.Clock
+
+// Option configures a DelayQueue created by New.
+type Option func(*options)
+
+type options struct {
+	clock Clock
+}
+
+// WithClock sets the clock the queue uses to decide when elements are due. Defaults to the system clock.
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+type item[T any] struct {
+	value T
+	at    time.Time
+	seq   uint64 // Keeps elements due at the same time in the order they were enqueued
+}
+
+// DelayQueue is a thread-safe queue where each element is scheduled for a point in time, and Take returns elements
+// in the order they become due.
+type DelayQueue[T any] struct {
+	pq      *pqueue.PriorityQueue[item[T]]
+	rw      sync.RWMutex
+	clock   Clock
+	seq     uint64
+	closed  bool
+	waiters notify.List   // Goroutines blocked in Take, guarded by rw
+	leader  chan struct{} // Waiter sleeping until the earliest element is due, the others wait until signalled
+}
+
+// New creates a new DelayQueue, configured by opts.
+func New[T any](opts ...Option) *DelayQueue[T] {
+	o := options{clock: datastructures.SystemClock}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &DelayQueue[T]{
+		pq: pqueue.New(func(a, b item[T]) bool {
+			if a.at.Equal(b.at) {
+				return a.seq < b.seq
+			}
+			return a.at.Before(b.at)
+		}),
+		clock: o.clock,
+	}
+}
+
+// EnqueueAt schedules value to become available at t. Panics with ErrClosed if the queue is closed.
+func (dq *DelayQueue[T]) EnqueueAt(value T, t time.Time) {
+	dq.rw.Lock()
+	defer dq.rw.Unlock()
+	if dq.closed {
+		panic(ErrClosed)
+	}
+	dq.seq++
+	dq.pq.Push(item[T]{value: value, at: t, seq: dq.seq})
+	if head, _ := dq.pq.Peek(); head.seq == dq.seq {
+		// The new element is due first, so whoever sleeps for the old head must recompute its deadline
+		dq.leader = nil
+		dq.waiters.Signal()
+	}
+}
+
+// EnqueueAfter schedules value to become available once d has elapsed.
+func (dq *DelayQueue[T]) EnqueueAfter(value T, d time.Duration) {
+	dq.EnqueueAt(value, dq.clock.Now().Add(d))
+}
+
+// Take removes and returns the earliest element, waiting until it is due. Returns ctx.Err() if the context is done
+// first, or ErrClosed if the queue is closed and drained.
+func (dq *DelayQueue[T]) Take(ctx context.Context) (T, error) {
+	dq.rw.Lock()
+	defer dq.rw.Unlock()
+	for {
+		head, err := dq.pq.Peek()
+		if err != nil {
+			if dq.closed {
+				var zero T
+				return zero, ErrClosed
+			}
+			if err := dq.waiters.Wait(ctx, &dq.rw); err != nil {
+				var zero T
+				return zero, err
+			}
+			continue
+		}
+		delay := head.at.Sub(dq.clock.Now())
+		if delay <= 0 {
+			dq.pq.Pop()
+			if !dq.pq.IsEmpty() {
+				// Let another waiter take over sleeping until the next element is due
+				dq.waiters.Signal()
+			}
+			return head.value, nil
+		}
+		if dq.leader != nil {
+			if err := dq.waiters.Wait(ctx, &dq.rw); err != nil {
+				var zero T
+				return zero, err
+			}
+			continue
+		}
+		if err := dq.waitUntilDue(ctx, delay); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+}
+
+// waitUntilDue parks the caller as the leader until delay has elapsed, it is signalled or ctx is done. Must be
+// called with dq.rw held, which is released while parked.
+func (dq *DelayQueue[T]) waitUntilDue(ctx context.Context, delay time.Duration) error {
+	wake := dq.waiters.Add()
+	dq.leader = wake
+	timer := dq.clock.After(delay)
+	dq.rw.Unlock()
+	var err error
+	select {
+	case <-wake:
+	case <-timer:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	dq.rw.Lock()
+	if dq.leader == wake {
+		dq.leader = nil
+	}
+	dq.waiters.Remove(wake)
+	if err != nil {
+		// Hand over to the next waiter, in case we were signalled as we gave up or nobody else sleeps until the
+		// earliest element is due
+		dq.waiters.Signal()
+	}
+	return err
+}
+
+// TryTake removes and returns the earliest element if it is due, without waiting. Returns ErrEmpty if no element is
+// due yet, or ErrClosed if the queue is closed and drained.
+func (dq *DelayQueue[T]) TryTake() (T, error) {
+	dq.rw.Lock()
+	defer dq.rw.Unlock()
+	head, err := dq.pq.Peek()
+	if err != nil {
+		if dq.closed {
+			return head.value, ErrClosed
+		}
+		return head.value, ErrEmpty
+	}
+	if head.at.After(dq.clock.Now()) {
+		var zero T
+		return zero, ErrEmpty
+	}
+	dq.pq.Pop()
+	return head.value, nil
+}
+
+// Close stops the queue from accepting new elements. Elements already in the queue are still returned once due,
+// after which Take returns ErrClosed. Goroutines blocked waiting on the queue are woken. Closing twice is a no-op.
+func (dq *DelayQueue[T]) Close() {
+	dq.rw.Lock()
+	defer dq.rw.Unlock()
+	dq.closed = true
+	dq.waiters.Broadcast()
+}
+
+// IsClosed checks if the queue has been closed.
+func (dq *DelayQueue[T]) IsClosed() bool {
+	dq.rw.RLock()
+	defer dq.rw.RUnlock()
+	return dq.closed
+}
+
+// Size returns the number of elements in the queue, due or not.
+func (dq *DelayQueue[T]) Size() int {
+	dq.rw.RLock()
+	defer dq.rw.RUnlock()
+	return dq.pq.Size()
+}
+
+// IsEmpty checks if the queue is empty.
+func (dq *DelayQueue[T]) IsEmpty() bool {
+	dq.rw.RLock()
+	defer dq.rw.RUnlock()
+	return dq.pq.IsEmpty()
+}
+
+// Clear removes all elements from the queue.
+func (dq *DelayQueue[T]) Clear() {
+	dq.rw.Lock()
+	defer dq.rw.Unlock()
+	dq.pq.Clear()
+}
+
+// ToSlice returns the elements of the queue in the order they become due.
+func (dq *DelayQueue[T]) ToSlice() []T {
+	dq.rw.RLock()
+	items := dq.pq.ToSlice()
+	dq.rw.RUnlock()
+	slices.SortFunc(items, func(a, b item[T]) int {
+		if c := a.at.Compare(b.at); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.seq, b.seq)
+	})
+	result := make([]T, len(items))
+	for i, it := range items {
+		result[i] = it.value
+	}
+	return result
+}
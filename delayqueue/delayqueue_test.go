@@ -0,0 +1,173 @@
+package delayqueue
+
+import (
+	"context"
+	"errors"
+	"github.com/Shreyas-Adireddy/data_structures/internal/fakeclock"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDelayQueue(t *testing.T) {
+	clock := fakeclock.New()
+	dq := New[string](WithClock(clock))
+	dq.EnqueueAfter("a", 3*time.Second)
+	dq.EnqueueAfter("b", time.Second)
+	dq.EnqueueAfter("c", 2*time.Second)
+	dq.EnqueueAfter("d", 2*time.Second)
+
+	if dq.Size() != 4 {
+		t.Errorf("Expected size to be 4, got %d", dq.Size())
+	}
+	if got, expected := dq.ToSlice(), []string{"b", "c", "d", "a"}; !slices.Equal(got, expected) {
+		t.Errorf("Expected ToSlice to return %v, got %v", expected, got)
+	}
+	if _, err := dq.TryTake(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty before anything is due, got %v", err)
+	}
+
+	clock.Advance(time.Second)
+	if val, err := dq.TryTake(); err != nil || val != "b" {
+		t.Errorf("Expected TryTake to return b, got %v (error: %v)", val, err)
+	}
+	if _, err := dq.TryTake(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+
+	clock.Advance(5 * time.Second)
+	for _, expected := range []string{"c", "d", "a"} {
+		if val, err := dq.TryTake(); err != nil || val != expected {
+			t.Errorf("Expected TryTake to return %s, got %v (error: %v)", expected, val, err)
+		}
+	}
+	if !dq.IsEmpty() {
+		t.Errorf("Expected queue to be empty, got size %d", dq.Size())
+	}
+}
+
+func TestTake(t *testing.T) {
+	clock := fakeclock.New()
+	dq := New[int](WithClock(clock))
+	dq.EnqueueAfter(1, 10*time.Second)
+
+	result := make(chan int)
+	go func() {
+		val, err := dq.Take(context.Background())
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		result <- val
+	}()
+	clock.WaitForTimers(t, 1)
+
+	// An element due earlier wakes the sleeping taker so it can recompute its deadline
+	dq.EnqueueAfter(2, time.Second)
+	clock.WaitForTimers(t, 2)
+	clock.Advance(time.Second)
+	if val := <-result; val != 2 {
+		t.Errorf("Expected Take to return 2, got %v", val)
+	}
+
+	go func() {
+		val, _ := dq.Take(context.Background())
+		result <- val
+	}()
+	clock.Advance(9 * time.Second)
+	if val := <-result; val != 1 {
+		t.Errorf("Expected Take to return 1, got %v", val)
+	}
+}
+
+func TestTakeContext(t *testing.T) {
+	dq := New[int](WithClock(fakeclock.New()))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, err := dq.Take(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded on an empty queue, got %v", err)
+	}
+
+	dq.EnqueueAfter(1, time.Hour)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, err := dq.Take(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded before the element is due, got %v", err)
+	}
+}
+
+// Several takers share the real clock, so one sleeps until the next element is due while the others wait their turn
+func TestMultipleTakers(t *testing.T) {
+	dq := New[int]()
+	const n = 100
+	for i := 0; i < n; i++ {
+		dq.EnqueueAfter(i, time.Duration(i%10)*time.Millisecond)
+	}
+
+	var mu sync.Mutex
+	var got []int
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				val, err := dq.Take(context.Background())
+				if errors.Is(err, ErrClosed) {
+					return
+				}
+				mu.Lock()
+				got = append(got, val)
+				if len(got) == n {
+					dq.Close()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	slices.Sort(got)
+	for i, val := range got {
+		if val != i {
+			t.Fatalf("Expected every element to be taken once, got %v", got)
+		}
+	}
+}
+
+func TestClose(t *testing.T) {
+	clock := fakeclock.New()
+	dq := New[int](WithClock(clock))
+	dq.EnqueueAfter(1, time.Second)
+	dq.Close()
+	if !dq.IsClosed() {
+		t.Error("Expected queue to be closed")
+	}
+
+	// Elements already queued are still delivered once due
+	result := make(chan error)
+	go func() {
+		val, err := dq.Take(context.Background())
+		if err == nil && val != 1 {
+			t.Errorf("Expected Take to return 1, got %v", val)
+		}
+		result <- err
+	}()
+	clock.WaitForTimers(t, 1)
+	clock.Advance(time.Second)
+	if err := <-result; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := dq.Take(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+	if _, err := dq.TryTake(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+
+	defer func() {
+		if r := recover(); r != ErrClosed {
+			t.Errorf("Expected EnqueueAt to panic with ErrClosed, got %v", r)
+		}
+	}()
+	dq.EnqueueAt(2, clock.Now())
+}
@@ -1,27 +1,90 @@
 package deque
 
 import (
-	"errors"
+	"github.com/Shreyas-Adireddy/data_structures"
+	"github.com/Shreyas-Adireddy/data_structures/internal/capacity"
+	"iter"
+	"slices"
 )
 
 const maxInt = int(^uint(0) >> 1)
 
+var (
+	// ErrEmpty is returned by the Pop and Peek methods when the deque has no elements.
+	ErrEmpty = datastructures.ErrEmpty
+	// ErrIndexOutOfRange is returned by the positional methods when an index is outside of the deque.
+	ErrIndexOutOfRange = datastructures.ErrIndexOutOfRange
+)
+
+var _ datastructures.Double[int] = (*Deque[int])(nil)
+
 // Deque represents a double-ended queue implemented with a circular array.
 type Deque[T any] struct {
 	data  []T
 	front int
 	rear  int
 	size  int
+	opts  options
+}
+
+// Option configures a Deque created by New.
+type Option func(*options)
+
+type options struct {
+	growth      datastructures.GrowthPolicy
+	noShrink    bool
+	minCapacity int // Capacity the automatic shrink never goes below, 8 if unset
+}
+
+// WithGrowth sets the policy used to grow the deque when it is full. Defaults to datastructures.SliceGrowth.
+func WithGrowth(policy datastructures.GrowthPolicy) Option {
+	return func(o *options) {
+		o.growth = policy
+	}
 }
 
-// New creates a new Deque with the given initial capacity.
-func New[T any]() *Deque[T] {
-	return &Deque[T]{
-		data:  make([]T, 8),
+// WithoutShrink stops the Pop methods from halving the capacity when the deque is no more than 1/4 full, which avoids
+// shrink-then-regrow oscillation under bursty workloads.
+func WithoutShrink() Option {
+	return func(o *options) {
+		o.noShrink = true
+	}
+}
+
+// WithMinCapacity sets the capacity the automatic shrink never goes below, and that Clear resets the deque to. The deque
+// starts with at least this capacity. Defaults to 8, or to the capacity passed to NewWithCapacity.
+func WithMinCapacity(capacity int) Option {
+	return func(o *options) {
+		o.minCapacity = max(capacity, 1)
+	}
+}
+
+// New creates a new Deque, configured by opts.
+func New[T any](opts ...Option) *Deque[T] {
+	d := &Deque[T]{
 		front: 0,
 		rear:  0,
 		size:  0,
 	}
+	for _, opt := range opts {
+		opt(&d.opts)
+	}
+	d.data = make([]T, max(8, d.minCapacity()))
+	return d
+}
+
+// NewWithCapacity creates a new Deque with room for capacity elements before it has to grow. The automatic shrink
+// never takes it below capacity, so a preallocated deque stays allocation-free while it doesn't outgrow it.
+func NewWithCapacity[T any](capacity int, opts ...Option) *Deque[T] {
+	capacity = max(capacity, 1)
+	d := &Deque[T]{
+		opts: options{minCapacity: capacity},
+	}
+	for _, opt := range opts {
+		opt(&d.opts)
+	}
+	d.data = make([]T, max(capacity, d.minCapacity()))
+	return d
 }
 
 // IsEmpty checks if the deque is empty.
@@ -34,25 +97,18 @@ func (d *Deque[T]) Size() int {
 	return d.size
 }
 
+// Cap returns the number of elements the deque holds before it has to grow.
+func (d *Deque[T]) Cap() int {
+	return len(d.data)
+}
+
 // AddFront adds an element to the front of the deque.
 func (d *Deque[T]) AddFront(value T) {
 	if d.size == maxInt {
 		panic("deque size (which is an int) is going to overflow")
 	}
 	if d.size == len(d.data) {
-		newCapacity := d.size
-		if d.size < 256 {
-			// If the queue is full and small, double the capacity
-			newCapacity += d.size
-		} else {
-			// If the queue is full and large, gradually go from 2x -> 1.25x capacity
-			newCapacity += (d.size + 3*256) >> 2
-		}
-		if newCapacity < 0 {
-			// If overflowed, set it to max int
-			newCapacity = maxInt
-		}
-		d.Resize(newCapacity)
+		d.grow(d.size + 1)
 	}
 	d.front = (d.front - 1 + len(d.data)) % len(d.data)
 	d.data[d.front] = value
@@ -65,41 +121,41 @@ func (d *Deque[T]) AddRear(value T) {
 		panic("deque size (which is an int) is going to overflow")
 	}
 	if d.size == len(d.data) {
-		newCapacity := d.size
-		if d.size < 256 {
-			// If the queue is full and small, double the capacity
-			newCapacity += d.size
-		} else {
-			// If the queue is full and large, gradually go from 2x -> 1.25x capacity
-			newCapacity += (d.size + 3*256) >> 2
-		}
-		if newCapacity < 0 {
-			// If overflowed, set it to max int
-			newCapacity = maxInt
-		}
-		d.Resize(newCapacity)
+		d.grow(d.size + 1)
 	}
 	d.data[d.rear] = value
 	d.rear = (d.rear + 1) % len(d.data)
 	d.size++
 }
 
+// AddRearAll adds values to the rear of the deque in order, resizing at most once.
+func (d *Deque[T]) AddRearAll(values ...T) {
+	if len(values) > maxInt-d.size {
+		panic("deque size (which is an int) is going to overflow")
+	}
+	if d.size+len(values) > len(d.data) {
+		d.grow(d.size + len(values))
+	}
+	// Copy in at most two segments: up to the end of the array, then wrapping around to the start
+	n := copy(d.data[d.rear:], values)
+	copy(d.data, values[n:])
+	d.rear = (d.rear + len(values)) % len(d.data)
+	d.size += len(values)
+}
+
 // PopFront removes and returns an element from the front of the deque.
 func (d *Deque[T]) PopFront() (T, error) {
 	if d.IsEmpty() {
 		var zero T
-		return zero, errors.New("deque is empty")
+		return zero, ErrEmpty
 	}
 	value := d.data[d.front]
+	// Clear the slot so the deque doesn't keep the value reachable
+	var zero T
+	d.data[d.front] = zero
 	d.front = (d.front + 1) % len(d.data)
 	d.size--
-	if d.size > 0 && d.size <= len(d.data)/4 {
-		newCapacity := len(d.data) / 2
-		if newCapacity < 8 {
-			newCapacity = 8
-		}
-		d.Resize(newCapacity)
-	}
+	d.shrink()
 	return value, nil
 }
 
@@ -107,26 +163,39 @@ func (d *Deque[T]) PopFront() (T, error) {
 func (d *Deque[T]) PopRear() (T, error) {
 	if d.IsEmpty() {
 		var zero T
-		return zero, errors.New("deque is empty")
+		return zero, ErrEmpty
 	}
 	d.rear = (d.rear - 1 + len(d.data)) % len(d.data)
 	value := d.data[d.rear]
+	// Clear the slot so the deque doesn't keep the value reachable
+	var zero T
+	d.data[d.rear] = zero
 	d.size--
-	if d.size > 0 && d.size <= len(d.data)/4 {
-		newCapacity := len(d.data) / 2
-		if newCapacity < 8 {
-			newCapacity = 8
-		}
-		d.Resize(newCapacity)
-	}
+	d.shrink()
 	return value, nil
 }
 
+// PopFrontN removes up to n elements from the front of the deque and returns them, front first.
+func (d *Deque[T]) PopFrontN(n int) []T {
+	n = max(min(n, d.size), 0)
+	result := make([]T, n)
+	// Copy in at most two segments: up to the end of the array, then wrapping around to the start
+	copied := copy(result, d.data[d.front:])
+	copy(result[copied:], d.data)
+	// Clear the vacated slots so the deque doesn't keep the values reachable
+	clear(d.data[d.front : d.front+copied])
+	clear(d.data[:n-copied])
+	d.front = (d.front + n) % len(d.data)
+	d.size -= n
+	d.shrink()
+	return result
+}
+
 // PeekFront returns the front element without removing it.
 func (d *Deque[T]) PeekFront() (T, error) {
 	if d.IsEmpty() {
 		var zero T
-		return zero, errors.New("deque is empty")
+		return zero, ErrEmpty
 	}
 	return d.data[d.front], nil
 }
@@ -135,15 +204,142 @@ func (d *Deque[T]) PeekFront() (T, error) {
 func (d *Deque[T]) PeekRear() (T, error) {
 	if d.IsEmpty() {
 		var zero T
-		return zero, errors.New("deque is empty")
+		return zero, ErrEmpty
 	}
 	rearIndex := (d.rear - 1 + len(d.data)) % len(d.data)
 	return d.data[rearIndex], nil
 }
 
+// At returns the element at position i, where position 0 is the front.
+func (d *Deque[T]) At(i int) (T, error) {
+	if i < 0 || i >= d.size {
+		var zero T
+		return zero, ErrIndexOutOfRange
+	}
+	return d.data[d.index(i)], nil
+}
+
+// Set replaces the element at position i.
+func (d *Deque[T]) Set(i int, value T) error {
+	if i < 0 || i >= d.size {
+		return ErrIndexOutOfRange
+	}
+	d.data[d.index(i)] = value
+	return nil
+}
+
+// Swap exchanges the elements at positions i and j.
+func (d *Deque[T]) Swap(i, j int) error {
+	if i < 0 || i >= d.size || j < 0 || j >= d.size {
+		return ErrIndexOutOfRange
+	}
+	a, b := d.index(i), d.index(j)
+	d.data[a], d.data[b] = d.data[b], d.data[a]
+	return nil
+}
+
+// Insert adds an element so that it ends up at position i, moving whichever side of the deque is shorter.
+// i may range from 0 (same as AddFront) to Size() (same as AddRear).
+func (d *Deque[T]) Insert(i int, value T) error {
+	if i < 0 || i > d.size {
+		return ErrIndexOutOfRange
+	}
+	if i < d.size/2 {
+		// Make room at the front, then shift the elements before i one slot towards it
+		d.AddFront(value)
+		for k := 0; k < i; k++ {
+			d.data[d.index(k)] = d.data[d.index(k+1)]
+		}
+	} else {
+		// Make room at the rear, then shift the elements from i one slot towards it
+		d.AddRear(value)
+		for k := d.size - 1; k > i; k-- {
+			d.data[d.index(k)] = d.data[d.index(k-1)]
+		}
+	}
+	d.data[d.index(i)] = value
+	return nil
+}
+
+// Remove removes and returns the element at position i, moving whichever side of the deque is shorter.
+func (d *Deque[T]) Remove(i int) (T, error) {
+	if i < 0 || i >= d.size {
+		var zero T
+		return zero, ErrIndexOutOfRange
+	}
+	value := d.data[d.index(i)]
+	if i < d.size/2 {
+		// Close the gap by shifting the elements before i towards the rear, then drop the front
+		for k := i; k > 0; k-- {
+			d.data[d.index(k)] = d.data[d.index(k-1)]
+		}
+		_, _ = d.PopFront()
+	} else {
+		// Close the gap by shifting the elements after i towards the front, then drop the rear
+		for k := i; k < d.size-1; k++ {
+			d.data[d.index(k)] = d.data[d.index(k+1)]
+		}
+		_, _ = d.PopRear()
+	}
+	return value, nil
+}
+
+// Rotate rotates the deque n steps to the right, so Rotate(1) moves the rear element to the front. A negative n
+// rotates to the left. It moves min(k, Size()-k) elements where k is n modulo Size(), and never reallocates.
+func (d *Deque[T]) Rotate(n int) {
+	if d.size == 0 {
+		return
+	}
+	k := n % d.size
+	if k < 0 {
+		k += d.size
+	}
+	if k == 0 {
+		return
+	}
+	if d.size == len(d.data) {
+		// A full ring has no gap, so rotating is just moving where it starts
+		d.front = (d.front - k + len(d.data)) % len(d.data)
+		d.rear = d.front
+		return
+	}
+	// Moved-from slots are cleared so the deque doesn't keep stale copies reachable
+	var zero T
+	if k <= d.size-k {
+		// Move k elements from the rear to the front
+		for ; k > 0; k-- {
+			d.rear = (d.rear - 1 + len(d.data)) % len(d.data)
+			d.front = (d.front - 1 + len(d.data)) % len(d.data)
+			d.data[d.front] = d.data[d.rear]
+			d.data[d.rear] = zero
+		}
+	} else {
+		// Move the other size-k elements from the front to the rear
+		for k = d.size - k; k > 0; k-- {
+			d.data[d.rear] = d.data[d.front]
+			d.data[d.front] = zero
+			d.rear = (d.rear + 1) % len(d.data)
+			d.front = (d.front + 1) % len(d.data)
+		}
+	}
+}
+
+// Reverse reverses the order of the elements in place.
+func (d *Deque[T]) Reverse() {
+	for i, j := 0, d.size-1; i < j; i, j = i+1, j-1 {
+		a, b := d.index(i), d.index(j)
+		d.data[a], d.data[b] = d.data[b], d.data[a]
+	}
+}
+
+// index maps a position in the deque to an index into the underlying array.
+func (d *Deque[T]) index(i int) int {
+	return (d.front + i) % len(d.data)
+}
+
 // Clear removes all elements from the deque.
 func (d *Deque[T]) Clear() {
-	d.data = make([]T, 8)
+	d.data = make([]T, d.minCapacity())
 	d.front = 0
 	d.rear = 0
 	d.size = 0
@@ -158,8 +354,76 @@ func (d *Deque[T]) ToSlice() []T {
 	return result
 }
 
-// Resize resizes the underlying array to the new capacity.
+// Clone returns a copy of the deque with the same capacity and options. The elements themselves are copied shallowly.
+func (d *Deque[T]) Clone() *Deque[T] {
+	clone := *d
+	clone.data = slices.Clone(d.data)
+	return &clone
+}
+
+// All returns an iterator over the elements of the deque from front to rear. It walks the underlying
+// array in place, so the deque must not be modified during iteration.
+func (d *Deque[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; i < d.size; i++ {
+			if !yield(d.data[(d.front+i)%len(d.data)]) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the elements of the deque from rear to front.
+func (d *Deque[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := d.size - 1; i >= 0; i-- {
+			if !yield(d.data[(d.front+i)%len(d.data)]) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns an iterator over the positions and elements of the deque, where position 0 is the front.
+func (d *Deque[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := 0; i < d.size; i++ {
+			if !yield(i, d.data[(d.front+i)%len(d.data)]) {
+				return
+			}
+		}
+	}
+}
+
+// grow resizes the deque so it holds at least minCapacity elements, according to its growth policy.
+func (d *Deque[T]) grow(minCapacity int) {
+	d.Resize(capacity.Grow(d.opts.growth, len(d.data), minCapacity))
+}
+
+// shrink halves the capacity while the deque is no more than 1/4 full, down to its minimum capacity, unless
+// shrinking is disabled.
+func (d *Deque[T]) shrink() {
+	if d.opts.noShrink {
+		return
+	}
+	if newCapacity := capacity.Shrink(d.size, len(d.data), d.minCapacity()); newCapacity < len(d.data) {
+		d.Resize(newCapacity)
+	}
+}
+
+// minCapacity returns the capacity the deque starts with after Clear and never shrinks below.
+func (d *Deque[T]) minCapacity() int {
+	if d.opts.minCapacity == 0 {
+		return 8
+	}
+	return d.opts.minCapacity
+}
+
+// Resize resizes the underlying array to the new capacity, but never below the number of elements.
 func (d *Deque[T]) Resize(newCapacity int) {
+	if newCapacity < d.size {
+		newCapacity = d.size
+	}
 	if newCapacity < 1 {
 		newCapacity = 1
 	}
@@ -169,5 +433,6 @@ func (d *Deque[T]) Resize(newCapacity int) {
 	}
 	d.data = newData
 	d.front = 0
-	d.rear = d.size
+	// A full deque wraps its rear back to the start
+	d.rear = d.size % newCapacity
 }
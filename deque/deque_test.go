@@ -1,6 +1,14 @@
 package deque
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"github.com/Shreyas-Adireddy/data_structures"
+	"github.com/Shreyas-Adireddy/data_structures/internal/gctest"
+	"runtime"
+	"slices"
 	"testing"
 )
 
@@ -51,13 +59,13 @@ func TestDeque(t *testing.T) {
 
 	// Test PopFront on an empty deque
 	deque.PopRear()
-	if _, err := deque.PopFront(); err == nil || err.Error() != "deque is empty" {
-		t.Errorf("Expected PopFront to return error 'deque is empty', got %v", err)
+	if _, err := deque.PopFront(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected PopFront to return ErrEmpty, got %v", err)
 	}
 
 	// Test PopRear on an empty deque
-	if _, err := deque.PopRear(); err == nil || err.Error() != "deque is empty" {
-		t.Errorf("Expected PopRear to return error 'deque is empty', got %v", err)
+	if _, err := deque.PopRear(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected PopRear to return ErrEmpty, got %v", err)
 	}
 
 	// Test Clear
@@ -76,3 +84,291 @@ func TestDeque(t *testing.T) {
 		t.Errorf("Expected deque data length to be 4 after Resize, got %d", len(deque.data))
 	}
 }
+
+func TestIterators(t *testing.T) {
+	d := New[int]()
+	for i := 0; i < 3; i++ {
+		d.AddFront(i)
+		d.AddRear(i + 10)
+	}
+	expected := []int{2, 1, 0, 10, 11, 12}
+	if got := slices.Collect(d.All()); !slices.Equal(got, expected) {
+		t.Errorf("All returned %v, expected %v", got, expected)
+	}
+	slices.Reverse(expected)
+	if got := slices.Collect(d.Backward()); !slices.Equal(got, expected) {
+		t.Errorf("Backward returned %v, expected %v", got, expected)
+	}
+	count := 0
+	for i, val := range d.Enumerate() {
+		if val != expected[len(expected)-1-i] {
+			t.Errorf("Enumerate returned %v at %v, expected %v", val, i, expected[len(expected)-1-i])
+		}
+		count++
+	}
+	if count != 6 {
+		t.Errorf("Expected Enumerate to visit 6 elements, visited %d", count)
+	}
+}
+
+func TestRandomAccess(t *testing.T) {
+	d := New[int]()
+	for i := 0; i < 6; i++ {
+		d.AddRear(i)
+	}
+	d.AddFront(-1)
+
+	if val, err := d.At(0); err != nil || val != -1 {
+		t.Errorf("Expected At(0) to return -1, got %v (error: %v)", val, err)
+	}
+	if _, err := d.At(7); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Expected ErrIndexOutOfRange, got %v", err)
+	}
+	if err := d.Set(3, 30); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := d.Swap(0, 6); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if expected := []int{5, 0, 1, 30, 3, 4, -1}; !slices.Equal(d.ToSlice(), expected) {
+		t.Errorf("Expected %v, got %v", expected, d.ToSlice())
+	}
+
+	// Insert and Remove near each end so both shift directions are exercised
+	expected := d.ToSlice()
+	for _, i := range []int{1, 6, 0, 9, 4} {
+		if err := d.Insert(i, 100+i); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		expected = slices.Insert(expected, i, 100+i)
+		if !slices.Equal(d.ToSlice(), expected) {
+			t.Errorf("After Insert(%d), expected %v, got %v", i, expected, d.ToSlice())
+		}
+	}
+	for _, i := range []int{1, 9, 0, 5, 3} {
+		val, err := d.Remove(i)
+		if err != nil || val != expected[i] {
+			t.Errorf("Expected Remove(%d) to return %v, got %v (error: %v)", i, expected[i], val, err)
+		}
+		expected = slices.Delete(expected, i, i+1)
+		if !slices.Equal(d.ToSlice(), expected) {
+			t.Errorf("After Remove(%d), expected %v, got %v", i, expected, d.ToSlice())
+		}
+	}
+	if err := d.Insert(-1, 0); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Expected ErrIndexOutOfRange, got %v", err)
+	}
+	if _, err := d.Remove(d.Size()); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Expected ErrIndexOutOfRange, got %v", err)
+	}
+}
+
+func TestRotateAndReverse(t *testing.T) {
+	// 8 elements fills the initial ring, 6 leaves a gap, so both rotation paths are covered
+	for _, size := range []int{6, 8} {
+		for _, n := range []int{0, 1, 2, 5, -1, -4, 13, -13} {
+			d := New[int]()
+			expected := make([]int, size)
+			for i := 0; i < size; i++ {
+				d.AddRear(i)
+				expected[i] = i
+			}
+			capacity := len(d.data)
+			d.Rotate(n)
+			k := ((n % size) + size) % size
+			expected = append(expected[size-k:], expected[:size-k]...)
+			if !slices.Equal(d.ToSlice(), expected) {
+				t.Errorf("Rotate(%d) of %d elements: expected %v, got %v", n, size, expected, d.ToSlice())
+			}
+			if len(d.data) != capacity {
+				t.Errorf("Rotate(%d) reallocated from %d to %d", n, capacity, len(d.data))
+			}
+			d.AddRear(100)
+			if val, _ := d.PeekRear(); val != 100 {
+				t.Errorf("Rotate(%d) left rear inconsistent, PeekRear returned %v", n, val)
+			}
+		}
+	}
+
+	d := New[int]()
+	d.Rotate(3)
+	for i := 0; i < 5; i++ {
+		d.AddFront(i)
+	}
+	d.Reverse()
+	if expected := []int{0, 1, 2, 3, 4}; !slices.Equal(d.ToSlice(), expected) {
+		t.Errorf("Reverse: expected %v, got %v", expected, d.ToSlice())
+	}
+}
+
+func TestBatchOperations(t *testing.T) {
+	d := New[int]()
+	d.AddFront(0)
+	d.AddRearAll(1, 2, 3, 4, 5, 6, 7, 8, 9)
+	if expected := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}; !slices.Equal(d.ToSlice(), expected) {
+		t.Errorf("Expected %v, got %v", expected, d.ToSlice())
+	}
+	if got, expected := d.PopFrontN(4), []int{0, 1, 2, 3}; !slices.Equal(got, expected) {
+		t.Errorf("PopFrontN returned %v, expected %v", got, expected)
+	}
+	if got, expected := d.PopFrontN(10), []int{4, 5, 6, 7, 8, 9}; !slices.Equal(got, expected) {
+		t.Errorf("PopFrontN returned %v, expected %v", got, expected)
+	}
+	if !d.IsEmpty() {
+		t.Error("Expected deque to be empty")
+	}
+}
+
+func TestGrowthPolicy(t *testing.T) {
+	d := New[int](WithGrowth(datastructures.ChunkGrowth(4)), WithoutShrink())
+	for i := 0; i < 10; i++ {
+		d.AddFront(i)
+	}
+	if capacity := len(d.data); capacity != 12 {
+		t.Errorf("Expected deque capacity to be 12, got %d", capacity)
+	}
+	for i := 0; i < 9; i++ {
+		_, _ = d.PopRear()
+	}
+	if capacity := len(d.data); capacity != 12 {
+		t.Errorf("Expected deque capacity to stay 12, got %d", capacity)
+	}
+}
+
+func TestNewWithCapacity(t *testing.T) {
+	d := NewWithCapacity[int](32)
+	for i := 0; i < 100; i++ {
+		d.AddFront(i)
+	}
+	if d.Cap() <= 32 {
+		t.Errorf("Expected deque to grow past 32, got capacity %d", d.Cap())
+	}
+	for i := 0; i < 99; i++ {
+		_, _ = d.PopFront()
+	}
+	if d.Cap() != 32 {
+		t.Errorf("Expected deque capacity to shrink back to 32, got %d", d.Cap())
+	}
+}
+
+func TestWithMinCapacity(t *testing.T) {
+	d := New[int](WithMinCapacity(32))
+	if d.Cap() != 32 {
+		t.Errorf("Expected deque capacity to be 32, got %d", d.Cap())
+	}
+	for i := 0; i < 1000; i++ {
+		d.AddRear(i)
+	}
+	for i := 0; i < 1000; i++ {
+		_, _ = d.PopRear()
+		if d.Cap() < 32 {
+			t.Fatalf("Expected the Pop methods never to shrink the deque below 32, got %d", d.Cap())
+		}
+	}
+	d.Clear()
+	if d.Cap() != 32 {
+		t.Errorf("Expected deque capacity to be 32 after Clear, got %d", d.Cap())
+	}
+}
+
+func TestRemovedElementsAreReleased(t *testing.T) {
+	removals := map[string]func(d *Deque[*gctest.Object]){
+		"PopFront":  func(d *Deque[*gctest.Object]) { _, _ = d.PopFront() },
+		"PopRear":   func(d *Deque[*gctest.Object]) { d.Rotate(-1); _, _ = d.PopRear() },
+		"PopFrontN": func(d *Deque[*gctest.Object]) { d.PopFrontN(1) },
+		"Remove":    func(d *Deque[*gctest.Object]) { _, _ = d.Remove(0) },
+		"Rotate":    func(d *Deque[*gctest.Object]) { d.Rotate(-1); d.Rotate(1); _, _ = d.PopFront() },
+	}
+	for name, remove := range removals {
+		t.Run(name, func(t *testing.T) {
+			d := New[*gctest.Object]()
+			obj, freed := gctest.Track()
+			d.AddRear(obj)
+			d.AddRear(&gctest.Object{})
+			d.AddRear(&gctest.Object{})
+			obj = nil
+			remove(d)
+			if !freed() {
+				t.Error("Expected removed element to be garbage collected")
+			}
+			runtime.KeepAlive(d)
+		})
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	d := New[int]()
+	for i := 3; i < 7; i++ {
+		d.AddRear(i)
+	}
+	// Add to the front so the logical order differs from the memory layout
+	for i := 2; i >= 0; i-- {
+		d.AddFront(i)
+	}
+	expected := []int{0, 1, 2, 3, 4, 5, 6}
+
+	data, err := json.Marshal(d)
+	if err != nil || string(data) != "[0,1,2,3,4,5,6]" {
+		t.Errorf("Expected MarshalJSON to return [0,1,2,3,4,5,6], got %s (error: %v)", data, err)
+	}
+	// Decoding into a zero value leaves a usable deque
+	var fromJSON Deque[int]
+	if err := json.Unmarshal(data, &fromJSON); err != nil || !slices.Equal(fromJSON.ToSlice(), expected) {
+		t.Errorf("Expected UnmarshalJSON to restore %v, got %v (error: %v)", expected, fromJSON.ToSlice(), err)
+	}
+	fromJSON.AddRear(7)
+	if fromJSON.Size() != len(expected)+1 {
+		t.Errorf("Expected size to be %d after decoding and adding, got %d", len(expected)+1, fromJSON.Size())
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(d); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fromGob := New[int]()
+	fromGob.AddRear(7)
+	if err := gob.NewDecoder(&buf).Decode(fromGob); err != nil || !slices.Equal(fromGob.ToSlice(), expected) {
+		t.Errorf("Expected GobDecode to replace the contents with %v, got %v (error: %v)", expected, fromGob.ToSlice(), err)
+	}
+
+	data, err = d.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var fromBinary Deque[int]
+	if err := fromBinary.UnmarshalBinary(data); err != nil || !slices.Equal(fromBinary.ToSlice(), expected) {
+		t.Errorf("Expected UnmarshalBinary to restore %v, got %v (error: %v)", expected, fromBinary.ToSlice(), err)
+	}
+	if err := fromBinary.UnmarshalJSON([]byte("{")); err == nil {
+		t.Error("Expected UnmarshalJSON to fail on invalid JSON")
+	}
+}
+
+func TestClone(t *testing.T) {
+	d := New[int]()
+	d.AddRear(2)
+	d.AddFront(1)
+	clone := d.Clone()
+	d.PopRear()
+	clone.AddFront(0)
+	if got := d.ToSlice(); !slices.Equal(got, []int{1}) {
+		t.Errorf("Expected the original to hold [1], got %v", got)
+	}
+	if got := clone.ToSlice(); !slices.Equal(got, []int{0, 1, 2}) {
+		t.Errorf("Expected the clone to hold [0 1 2], got %v", got)
+	}
+}
+
+func TestResizeToSize(t *testing.T) {
+	d := New[int]()
+	d.AddRearAll(1, 2, 3)
+	d.Resize(1)
+	if d.Cap() != 3 {
+		t.Errorf("Expected Resize to keep room for every element, got capacity %d", d.Cap())
+	}
+	d.PopFront()
+	d.AddRear(4)
+	if got := d.ToSlice(); !slices.Equal(got, []int{2, 3, 4}) {
+		t.Errorf("Expected [2 3 4] after resizing to the size, got %v", got)
+	}
+}
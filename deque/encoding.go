@@ -0,0 +1,41 @@
+package deque
+
+import "github.com/Shreyas-Adireddy/data_structures/internal/codec"
+
+// MarshalJSON encodes the deque as a JSON array from front to rear.
+func (d *Deque[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalJSON(d.ToSlice())
+}
+
+// UnmarshalJSON replaces the contents of the deque with a JSON array, the first element becoming the front.
+func (d *Deque[T]) UnmarshalJSON(data []byte) error {
+	return codec.UnmarshalJSON(data, d.replace)
+}
+
+// GobEncode encodes the deque from front to rear, independent of how it is laid out in memory.
+func (d *Deque[T]) GobEncode() ([]byte, error) {
+	return codec.GobEncode(d.ToSlice())
+}
+
+// GobDecode replaces the contents of the deque with data encoded by GobEncode.
+func (d *Deque[T]) GobDecode(data []byte) error {
+	return codec.GobDecode(data, d.replace)
+}
+
+// MarshalBinary encodes the deque with encoding/gob, same as GobEncode.
+func (d *Deque[T]) MarshalBinary() ([]byte, error) {
+	return d.GobEncode()
+}
+
+// UnmarshalBinary replaces the contents of the deque with data encoded by MarshalBinary.
+func (d *Deque[T]) UnmarshalBinary(data []byte) error {
+	return d.GobDecode(data)
+}
+
+// replace replaces the contents of the deque with values, the first becoming the front. Works on a zero Deque too. It
+// never fails, the error only matches what codec expects.
+func (d *Deque[T]) replace(values []T) error {
+	d.Clear()
+	d.AddRearAll(values...)
+	return nil
+}
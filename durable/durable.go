@@ -0,0 +1,613 @@
+// Package durable provides a queue that survives process crashes by appending every operation to a write-ahead log
+// on local disk.
+package durable
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"github.com/Shreyas-Adireddy/data_structures"
+	"github.com/Shreyas-Adireddy/data_structures/queue"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrEmpty is returned by Dequeue, Front and Back when the queue has no elements.
+	ErrEmpty = datastructures.ErrEmpty
+	// ErrClosed is returned by writes to a queue after Close.
+	ErrClosed = datastructures.ErrClosed
+	// ErrCorrupt is returned by Open when a segment other than the last one has a damaged record.
+	ErrCorrupt = errors.New("write-ahead log is corrupt")
+	// ErrLocked is returned by Open when another Queue already has the directory open.
+	ErrLocked = errors.New("queue directory is already open")
+)
+
+// SyncPolicy decides when writes to the log are flushed to stable storage with fsync.
+type SyncPolicy int
+
+const (
+	// SyncAlways flushes after every write, so nothing acknowledged is lost in a crash.
+	SyncAlways SyncPolicy = iota
+	// SyncInterval flushes in the background at a fixed interval, losing at most one interval of writes.
+	SyncInterval
+	// SyncNever leaves flushing writes to the operating system. A segment is still flushed once it is full, and
+	// creating and deleting segments is flushed too, so a crash can only tear the end of the log.
+	SyncNever
+)
+
+const (
+	recordEnqueue byte = iota + 1
+	recordDequeue
+	recordClear
+)
+
+// Each record is laid out as payload length (4 bytes), CRC-32 of the rest (4 bytes), type (1 byte),
+// sequence number (8 bytes) and payload
+const headerSize = 4 + 4 + 1 + 8
+
+const segmentExt = ".wal"
+
+// lockName is the file in the queue directory that Open locks, so only one Queue writes to the log at a time
+const lockName = "LOCK"
+
+// Option configures a Queue opened by Open.
+type Option func(*options)
+
+type options struct {
+	sync         SyncPolicy
+	syncInterval time.Duration
+	segmentSize  int64
+}
+
+// WithSync sets when writes are flushed to disk. Defaults to SyncAlways.
+func WithSync(policy SyncPolicy) Option {
+	return func(o *options) {
+		o.sync = policy
+	}
+}
+
+// WithSyncInterval flushes writes to disk every d, and implies SyncInterval.
+func WithSyncInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.sync = SyncInterval
+		o.syncInterval = d
+	}
+}
+
+// WithSegmentSize sets the size in bytes after which the log moves on to a new segment file. Fully consumed segments
+// are deleted, so smaller segments reclaim disk space sooner. Defaults to 64 MiB.
+func WithSegmentSize(size int64) Option {
+	return func(o *options) {
+		o.segmentSize = size
+	}
+}
+
+type entry[T any] struct {
+	seq   uint64
+	value T
+}
+
+type segment struct {
+	index  uint64
+	maxSeq uint64 // Sequence number of the last element enqueued in the segment, 0 if none
+}
+
+// Queue is a FIFO queue backed by a segmented, checksummed log in a directory. Enqueue, Dequeue and Clear append a
+// record to the log before changing the in-memory copy of the queue, and Open replays the log to rebuild it.
+// Dequeue removes an element for good, so for at-least-once processing read it with Front and only Dequeue it once
+// it has been handled. It is safe for concurrent use.
+type Queue[T any] struct {
+	mu       sync.Mutex
+	dir      string
+	opts     options
+	q        *queue.Queue[entry[T]]
+	nextSeq  uint64
+	segments []segment // Oldest first, the last one is being appended to
+	file     *os.File
+	lock     *os.File
+	size     int64 // Size of the segment being appended to
+	dirty    bool  // Whether there are writes SyncInterval hasn't flushed yet
+	broken   error // Failure that left the log out of step with memory, returned by every later write
+	closed   bool
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// Open opens the queue stored in dir, creating the directory if needed, and replays its log. A record torn by a
+// crash at the end of the log is discarded. Returns ErrLocked if another Queue has the directory open, until that
+// one is closed.
+func Open[T any](dir string, opts ...Option) (_ *Queue[T], err error) {
+	o := options{syncInterval: time.Second, segmentSize: 64 << 20}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.syncInterval <= 0 {
+		o.syncInterval = time.Second
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	lock, err := lockDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			lock.Close()
+		}
+	}()
+	dq := &Queue[T]{
+		dir:     dir,
+		opts:    o,
+		q:       queue.New[entry[T]](),
+		nextSeq: 1,
+		lock:    lock,
+	}
+	indexes, err := listSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+	tail, err := dq.logTail(indexes)
+	if err != nil {
+		return nil, err
+	}
+	for i, index := range indexes {
+		if err := dq.replay(index, i >= tail); err != nil {
+			return nil, err
+		}
+	}
+	if len(dq.segments) == 0 {
+		dq.segments = append(dq.segments, segment{index: 1})
+	}
+	active := dq.segments[len(dq.segments)-1]
+	dq.file, err = os.OpenFile(dq.segmentPath(active.index), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := dq.file.Stat()
+	if err == nil {
+		// The segment may have just been created
+		err = syncDir(dir)
+	}
+	if err != nil {
+		dq.file.Close()
+		return nil, err
+	}
+	dq.size = info.Size()
+	dq.compact()
+	if o.sync == SyncInterval {
+		dq.stop = make(chan struct{})
+		dq.done = make(chan struct{})
+		go dq.syncLoop()
+	}
+	return dq, nil
+}
+
+// Enqueue appends to the rear of the queue.
+func (dq *Queue[T]) Enqueue(value T) error {
+	return dq.EnqueueAll(value)
+}
+
+// EnqueueAll appends values to the rear of the queue in order, with a single write and flush.
+func (dq *Queue[T]) EnqueueAll(values ...T) error {
+	dq.mu.Lock()
+	defer dq.mu.Unlock()
+	if dq.closed {
+		return ErrClosed
+	}
+	var buf bytes.Buffer
+	entries := make([]entry[T], len(values))
+	for i, value := range values {
+		var payload bytes.Buffer
+		if err := gob.NewEncoder(&payload).Encode(value); err != nil {
+			return err
+		}
+		entries[i] = entry[T]{seq: dq.nextSeq + uint64(i), value: value}
+		appendRecord(&buf, recordEnqueue, entries[i].seq, payload.Bytes())
+	}
+	if err := dq.write(buf.Bytes()); err != nil {
+		return err
+	}
+	dq.nextSeq += uint64(len(values))
+	if len(values) > 0 {
+		dq.segments[len(dq.segments)-1].maxSeq = dq.nextSeq - 1
+	}
+	dq.q.EnqueueAll(entries...)
+	return nil
+}
+
+// Dequeue pops from the front of the queue, once the removal is in the log.
+func (dq *Queue[T]) Dequeue() (T, error) {
+	dq.mu.Lock()
+	defer dq.mu.Unlock()
+	if dq.closed {
+		var zero T
+		return zero, ErrClosed
+	}
+	front, err := dq.q.Front()
+	if err != nil {
+		return front.value, err
+	}
+	var buf bytes.Buffer
+	appendRecord(&buf, recordDequeue, front.seq, nil)
+	if err := dq.write(buf.Bytes()); err != nil {
+		var zero T
+		return zero, err
+	}
+	dq.q.Dequeue()
+	dq.compact()
+	return front.value, nil
+}
+
+// Front returns the front element of the queue without removing it.
+func (dq *Queue[T]) Front() (T, error) {
+	dq.mu.Lock()
+	defer dq.mu.Unlock()
+	front, err := dq.q.Front()
+	return front.value, err
+}
+
+// Back returns the rear element of the queue without removing it.
+func (dq *Queue[T]) Back() (T, error) {
+	dq.mu.Lock()
+	defer dq.mu.Unlock()
+	back, err := dq.q.Back()
+	return back.value, err
+}
+
+// Size returns the number of elements in the queue.
+func (dq *Queue[T]) Size() int {
+	dq.mu.Lock()
+	defer dq.mu.Unlock()
+	return dq.q.Size()
+}
+
+// IsEmpty checks if the queue is empty.
+func (dq *Queue[T]) IsEmpty() bool {
+	dq.mu.Lock()
+	defer dq.mu.Unlock()
+	return dq.q.IsEmpty()
+}
+
+// Clear removes all elements from the queue.
+func (dq *Queue[T]) Clear() error {
+	dq.mu.Lock()
+	defer dq.mu.Unlock()
+	if dq.closed {
+		return ErrClosed
+	}
+	var buf bytes.Buffer
+	appendRecord(&buf, recordClear, dq.nextSeq, nil)
+	if err := dq.write(buf.Bytes()); err != nil {
+		return err
+	}
+	dq.q.Clear()
+	dq.compact()
+	return nil
+}
+
+// ToSlice converts the queue to a slice and returns it.
+func (dq *Queue[T]) ToSlice() []T {
+	dq.mu.Lock()
+	defer dq.mu.Unlock()
+	result := make([]T, 0, dq.q.Size())
+	for e := range dq.q.All() {
+		result = append(result, e.value)
+	}
+	return result
+}
+
+// Sync flushes writes to disk, whatever the sync policy.
+func (dq *Queue[T]) Sync() error {
+	dq.mu.Lock()
+	defer dq.mu.Unlock()
+	if dq.closed {
+		return ErrClosed
+	}
+	return dq.sync()
+}
+
+// Close flushes the log, unless the sync policy is SyncNever, and closes it. Writes after Close return ErrClosed,
+// while reads still see the contents. Closing twice is a no-op.
+func (dq *Queue[T]) Close() error {
+	dq.mu.Lock()
+	if dq.closed {
+		dq.mu.Unlock()
+		return nil
+	}
+	dq.closed = true
+	dq.mu.Unlock()
+	if dq.stop != nil {
+		close(dq.stop)
+		<-dq.done
+	}
+	// Nothing else touches the file once closed is set and the sync loop is gone
+	var err error
+	if dq.opts.sync != SyncNever {
+		err = dq.sync()
+	}
+	return errors.Join(err, dq.file.Close(), dq.lock.Close())
+}
+
+// syncLoop flushes writes every sync interval until Close.
+func (dq *Queue[T]) syncLoop() {
+	defer close(dq.done)
+	ticker := time.NewTicker(dq.opts.syncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			dq.mu.Lock()
+			if !dq.closed {
+				// A failed flush is retried on the next tick, and Close reports it if it keeps failing
+				_ = dq.sync()
+			}
+			dq.mu.Unlock()
+		case <-dq.stop:
+			return
+		}
+	}
+}
+
+// write appends records to the log, moving on to a new segment first if the current one is full, and flushes them
+// if the sync policy says so. Must be called with dq.mu held.
+func (dq *Queue[T]) write(records []byte) error {
+	if dq.broken != nil {
+		return dq.broken
+	}
+	if dq.size > 0 && dq.size+int64(len(records)) > dq.opts.segmentSize {
+		if err := dq.rotate(); err != nil {
+			return err
+		}
+	}
+	if _, err := dq.file.Write(records); err != nil {
+		// Drop a partial write so later records don't end up behind a damaged one
+		_ = dq.file.Truncate(dq.size)
+		return err
+	}
+	dq.dirty = true
+	if dq.opts.sync == SyncAlways {
+		if err := dq.sync(); err != nil {
+			// The caller reports the write as failed, so the records must not replay. If they can't be taken back
+			// out, later writes would reuse their sequence numbers, so refuse them instead.
+			if terr := dq.file.Truncate(dq.size); terr != nil {
+				dq.broken = fmt.Errorf("log is out of step after a failed flush: %w", errors.Join(err, terr))
+			}
+			return err
+		}
+	}
+	dq.size += int64(len(records))
+	return nil
+}
+
+// rotate closes the current segment and starts a new one. Must be called with dq.mu held.
+func (dq *Queue[T]) rotate() error {
+	// Flush the full segment whatever the sync policy, since replay only forgives damage at the end of the log. It
+	// happens once per segment, so it is cheap.
+	if err := dq.sync(); err != nil {
+		return err
+	}
+	// Open the new segment before letting go of the current one, so a failure leaves the queue writable
+	index := dq.segments[len(dq.segments)-1].index + 1
+	file, err := os.OpenFile(dq.segmentPath(index), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := syncDir(dq.dir); err != nil {
+		file.Close()
+		return err
+	}
+	old := dq.file
+	dq.file = file
+	dq.size = 0
+	dq.dirty = false
+	dq.segments = append(dq.segments, segment{index: index})
+	return old.Close()
+}
+
+// sync flushes the current segment if it has unflushed writes. Must be called with dq.mu held.
+func (dq *Queue[T]) sync() error {
+	if !dq.dirty {
+		return nil
+	}
+	if err := dq.file.Sync(); err != nil {
+		return err
+	}
+	dq.dirty = false
+	return nil
+}
+
+// compact deletes the oldest segments while every element enqueued in them has been dequeued. The segment being
+// appended to is never deleted. Must be called with dq.mu held.
+func (dq *Queue[T]) compact() {
+	consumed := dq.nextSeq
+	if front, err := dq.q.Front(); err == nil {
+		consumed = front.seq
+	}
+	removed := false
+	for len(dq.segments) > 1 && dq.segments[0].maxSeq < consumed {
+		if err := os.Remove(dq.segmentPath(dq.segments[0].index)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			// Keep the segment and try again after the next Dequeue
+			break
+		}
+		dq.segments = dq.segments[1:]
+		removed = true
+	}
+	if removed {
+		// A removal lost in a crash only brings back a consumed segment, which replays harmlessly
+		_ = syncDir(dq.dir)
+	}
+}
+
+// replay applies the records of a segment to the in-memory queue. If the segment is at the end of the log, a damaged
+// record at its end is taken to be a write torn by a crash, so the segment is truncated before it.
+func (dq *Queue[T]) replay(index uint64, last bool) error {
+	path := dq.segmentPath(index)
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	seg := segment{index: index}
+	r := bufio.NewReader(file)
+	var offset int64
+	for {
+		kind, seq, payload, err := readRecord(r, info.Size()-offset)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if !last {
+				return fmt.Errorf("%w: segment %s at offset %d: %v", ErrCorrupt, filepath.Base(path), offset, err)
+			}
+			if err := os.Truncate(path, offset); err != nil {
+				return err
+			}
+			break
+		}
+		if err := dq.apply(&seg, kind, seq, payload); err != nil {
+			return fmt.Errorf("%w: segment %s at offset %d: %v", ErrCorrupt, filepath.Base(path), offset, err)
+		}
+		offset += headerSize + int64(len(payload))
+	}
+	dq.segments = append(dq.segments, seg)
+	return nil
+}
+
+// apply replays a single record.
+func (dq *Queue[T]) apply(seg *segment, kind byte, seq uint64, payload []byte) error {
+	switch kind {
+	case recordEnqueue:
+		var value T
+		if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&value); err != nil {
+			return err
+		}
+		dq.q.Enqueue(entry[T]{seq: seq, value: value})
+		seg.maxSeq = seq
+		dq.nextSeq = max(dq.nextSeq, seq+1)
+	case recordDequeue:
+		front, err := dq.q.Front()
+		switch {
+		case err == nil && front.seq == seq:
+			dq.q.Dequeue()
+		case err != nil || seq < front.seq:
+			// The element was in a segment that has already been compacted
+		default:
+			return fmt.Errorf("dequeue of %d while %d is at the front", seq, front.seq)
+		}
+		dq.nextSeq = max(dq.nextSeq, seq+1)
+	case recordClear:
+		dq.q.Clear()
+		dq.nextSeq = max(dq.nextSeq, seq)
+	default:
+		return fmt.Errorf("unknown record type %d", kind)
+	}
+	return nil
+}
+
+// syncDir flushes the directory entry changes in dir, so created and removed segments survive a crash.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// logTail returns the position in indexes of the last segment with any records. A crash can leave empty segments
+// behind it, created just before the crash, so a torn write at its end is still at the end of the log.
+func (dq *Queue[T]) logTail(indexes []uint64) (int, error) {
+	for i := len(indexes) - 1; i > 0; i-- {
+		info, err := os.Stat(dq.segmentPath(indexes[i]))
+		if err != nil {
+			return 0, err
+		}
+		if info.Size() > 0 {
+			return i, nil
+		}
+	}
+	return 0, nil
+}
+
+func (dq *Queue[T]) segmentPath(index uint64) string {
+	return filepath.Join(dq.dir, fmt.Sprintf("%020d%s", index, segmentExt))
+}
+
+// listSegments returns the indexes of the segments in dir in ascending order.
+func listSegments(dir string) ([]uint64, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var indexes []uint64
+	for _, f := range files {
+		name, ok := strings.CutSuffix(f.Name(), segmentExt)
+		if !ok || f.IsDir() {
+			continue
+		}
+		index, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+	slices.Sort(indexes)
+	return indexes, nil
+}
+
+func appendRecord(buf *bytes.Buffer, kind byte, seq uint64, payload []byte) {
+	var header [headerSize]byte
+	binary.LittleEndian.PutUint32(header[0:4], uint32(len(payload)))
+	header[8] = kind
+	binary.LittleEndian.PutUint64(header[9:17], seq)
+	crc := crc32.NewIEEE()
+	crc.Write(header[8:])
+	crc.Write(payload)
+	binary.LittleEndian.PutUint32(header[4:8], crc.Sum32())
+	buf.Write(header[:])
+	buf.Write(payload)
+}
+
+// readRecord reads the next record from a segment with remaining bytes left. Returns io.EOF only at a clean end of
+// the segment.
+func readRecord(r io.Reader, remaining int64) (kind byte, seq uint64, payload []byte, err error) {
+	var header [headerSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, 0, nil, err
+	}
+	length := int64(binary.LittleEndian.Uint32(header[0:4]))
+	if length > remaining-headerSize {
+		// Don't trust a damaged length with a huge allocation
+		return 0, 0, nil, io.ErrUnexpectedEOF
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, 0, nil, err
+	}
+	crc := crc32.NewIEEE()
+	crc.Write(header[8:])
+	crc.Write(payload)
+	if crc.Sum32() != binary.LittleEndian.Uint32(header[4:8]) {
+		return 0, 0, nil, errors.New("checksum mismatch")
+	}
+	return header[8], binary.LittleEndian.Uint64(header[9:17]), payload, nil
+}
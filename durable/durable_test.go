@@ -0,0 +1,331 @@
+package durable
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func segmentFiles(t *testing.T, dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return files
+}
+
+func TestDurableQueue(t *testing.T) {
+	dir := t.TempDir()
+	dq, err := Open[string](dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := dq.Dequeue(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+	for _, s := range []string{"a", "b", "c"} {
+		if err := dq.Enqueue(s); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if err := dq.EnqueueAll("d", "e"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if val, err := dq.Dequeue(); err != nil || val != "a" {
+		t.Errorf("Expected Dequeue to return a, got %v (error: %v)", val, err)
+	}
+	if front, _ := dq.Front(); front != "b" {
+		t.Errorf("Expected Front to return b, got %v", front)
+	}
+	if back, _ := dq.Back(); back != "e" {
+		t.Errorf("Expected Back to return e, got %v", back)
+	}
+	if err := dq.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := dq.Enqueue("f"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+
+	dq, err = Open[string](dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer dq.Close()
+	if got, expected := dq.ToSlice(), []string{"b", "c", "d", "e"}; !slices.Equal(got, expected) {
+		t.Errorf("Expected the reopened queue to hold %v, got %v", expected, got)
+	}
+	// Sequence numbers carry on, so later dequeues still replay correctly
+	dq.Enqueue("f")
+	dq.Dequeue()
+	dq.Close()
+	reopened, err := Open[string](dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer reopened.Close()
+	if got, expected := reopened.ToSlice(), []string{"c", "d", "e", "f"}; !slices.Equal(got, expected) {
+		t.Errorf("Expected the reopened queue to hold %v, got %v", expected, got)
+	}
+}
+
+// Under SyncAlways every write is on disk once it returns, so Close only releases the files and the log is what a
+// crash would leave behind
+func TestCrashRecovery(t *testing.T) {
+	type job struct {
+		ID   int
+		Name string
+	}
+	dir := t.TempDir()
+	dq, err := Open[job](dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		dq.Enqueue(job{i, "job"})
+	}
+	dq.Dequeue()
+	dq.Close()
+
+	recovered, err := Open[job](dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if recovered.Size() != 4 {
+		t.Errorf("Expected 4 jobs after recovery, got %d", recovered.Size())
+	}
+	if front, _ := recovered.Front(); front != (job{1, "job"}) {
+		t.Errorf("Expected job 1 at the front, got %v", front)
+	}
+	recovered.Close()
+}
+
+func TestLocked(t *testing.T) {
+	if !canLock {
+		t.Skip("Directories can't be locked on this platform")
+	}
+	dir := t.TempDir()
+	dq, err := Open[int](dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := Open[int](dir); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked, got %v", err)
+	}
+	dq.Close()
+	dq, err = Open[int](dir)
+	if err != nil {
+		t.Fatalf("Expected Open to succeed once the queue is closed, got %v", err)
+	}
+	dq.Close()
+}
+
+func TestTornWrite(t *testing.T) {
+	dir := t.TempDir()
+	dq, _ := Open[int](dir)
+	dq.EnqueueAll(1, 2, 3)
+	dq.Close()
+
+	// A crash in the middle of a write leaves half a record at the end of the last segment
+	files := segmentFiles(t, dir)
+	f, _ := os.OpenFile(files[len(files)-1], os.O_WRONLY|os.O_APPEND, 0)
+	f.Write([]byte{42, 0, 0, 0, 1, 2, 3})
+	f.Close()
+
+	dq, err := Open[int](dir)
+	if err != nil {
+		t.Fatalf("Expected the torn record to be discarded, got error %v", err)
+	}
+	if got := dq.ToSlice(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Expected [1 2 3], got %v", got)
+	}
+	// The log is truncated before the torn record, so new records replay after the old ones
+	dq.Enqueue(4)
+	dq.Close()
+	dq, err = Open[int](dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer dq.Close()
+	if got := dq.ToSlice(); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Errorf("Expected [1 2 3 4], got %v", got)
+	}
+}
+
+// Segments are flushed before the next one is created, but a crash can still tear the last records of a segment
+// whose successor was created and never written to
+func TestTornWriteBeforeEmptySegment(t *testing.T) {
+	dir := t.TempDir()
+	dq, _ := Open[int](dir, WithSync(SyncNever), WithSegmentSize(64))
+	for i := 0; i < 10; i++ {
+		dq.Enqueue(i)
+	}
+	dq.Close()
+
+	files := segmentFiles(t, dir)
+	last := files[len(files)-1]
+	data, _ := os.ReadFile(last)
+	os.WriteFile(last, append(data, 42, 0, 0, 0, 1, 2, 3), 0o644)
+	next := filepath.Join(dir, fmt.Sprintf("%020d%s", len(files)+1, segmentExt))
+	os.WriteFile(next, nil, 0o644)
+
+	dq, err := Open[int](dir, WithSegmentSize(64))
+	if err != nil {
+		t.Fatalf("Expected the torn record to be discarded, got error %v", err)
+	}
+	defer dq.Close()
+	if got := dq.ToSlice(); !slices.Equal(got, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}) {
+		t.Errorf("Expected [0 1 2 3 4 5 6 7 8 9], got %v", got)
+	}
+}
+
+func TestCorruptSegment(t *testing.T) {
+	dir := t.TempDir()
+	dq, _ := Open[int](dir, WithSegmentSize(64))
+	for i := 0; i < 20; i++ {
+		dq.Enqueue(i)
+	}
+	dq.Close()
+
+	files := segmentFiles(t, dir)
+	if len(files) < 2 {
+		t.Fatalf("Expected several segments, got %d", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	data[len(data)-1] ^= 0xff
+	os.WriteFile(files[0], data, 0o644)
+
+	if _, err := Open[int](dir); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Expected ErrCorrupt, got %v", err)
+	}
+}
+
+func TestCompaction(t *testing.T) {
+	dir := t.TempDir()
+	dq, err := Open[int](dir, WithSegmentSize(128))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		dq.Enqueue(i)
+	}
+	before := len(segmentFiles(t, dir))
+	for i := 0; i < 90; i++ {
+		dq.Dequeue()
+	}
+	after := len(segmentFiles(t, dir))
+	if after >= before {
+		t.Errorf("Expected consumed segments to be deleted, had %d and now %d", before, after)
+	}
+	dq.Close()
+
+	dq, err = Open[int](dir, WithSegmentSize(128))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []int{90, 91, 92, 93, 94, 95, 96, 97, 98, 99}
+	if got := dq.ToSlice(); !slices.Equal(got, expected) {
+		t.Errorf("Expected %v after compaction, got %v", expected, got)
+	}
+
+	if err := dq.Clear(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if files := segmentFiles(t, dir); len(files) != 1 {
+		t.Errorf("Expected only the active segment after Clear, got %d", len(files))
+	}
+	dq.Close()
+	dq, _ = Open[int](dir)
+	defer dq.Close()
+	if !dq.IsEmpty() {
+		t.Errorf("Expected queue to be empty after Clear, got %v", dq.ToSlice())
+	}
+}
+
+func TestSyncPolicies(t *testing.T) {
+	for name, opt := range map[string]Option{
+		"interval": WithSyncInterval(time.Millisecond),
+		"never":    WithSync(SyncNever),
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			dq, err := Open[int](dir, opt)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			dq.EnqueueAll(1, 2)
+			time.Sleep(5 * time.Millisecond)
+			if err := dq.Sync(); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if err := dq.Close(); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			dq, _ = Open[int](dir)
+			defer dq.Close()
+			if got := dq.ToSlice(); !slices.Equal(got, []int{1, 2}) {
+				t.Errorf("Expected [1 2], got %v", got)
+			}
+		})
+	}
+}
+
+// A segment that can't be created fails the write, but leaves the current segment open for the next one
+func TestRotateFailure(t *testing.T) {
+	dir := t.TempDir()
+	dq, err := Open[int](dir, WithSegmentSize(1))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer dq.Close()
+	dq.Enqueue(1)
+
+	blocker := filepath.Join(dir, fmt.Sprintf("%020d%s", 2, segmentExt))
+	if err := os.Mkdir(blocker, 0o755); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := dq.Enqueue(2); err == nil {
+		t.Error("Expected Enqueue to fail when the next segment can't be created")
+	}
+	os.Remove(blocker)
+	if err := dq.Enqueue(3); err != nil {
+		t.Errorf("Expected Enqueue to succeed once the segment can be created, got %v", err)
+	}
+	if got := dq.ToSlice(); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("Expected [1 3], got %v", got)
+	}
+}
+
+// A write whose flush fails must not leave records behind that reuse sequence numbers later
+func TestSyncFailure(t *testing.T) {
+	dir := t.TempDir()
+	dq, err := Open[int](dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	dq.Enqueue(1)
+
+	// Writes to a pipe succeed, but it can be neither flushed nor truncated
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer r.Close()
+	segment := dq.file
+	defer segment.Close()
+	dq.file = w
+	if err := dq.Enqueue(2); err == nil {
+		t.Fatal("Expected Enqueue to fail when the flush fails")
+	}
+	if err := dq.Enqueue(3); err == nil {
+		t.Error("Expected later writes to fail once the log is out of step")
+	}
+	if got := dq.ToSlice(); !slices.Equal(got, []int{1}) {
+		t.Errorf("Expected [1], got %v", got)
+	}
+	dq.Close()
+}
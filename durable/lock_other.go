@@ -0,0 +1,16 @@
+//go:build !unix
+
+package durable
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const canLock = false
+
+// lockDir creates the lock file but can't lock it on this platform, so it is up to the caller not to open the same
+// directory twice.
+func lockDir(dir string) (*os.File, error) {
+	return os.OpenFile(filepath.Join(dir, lockName), os.O_RDWR|os.O_CREATE, 0o644)
+}
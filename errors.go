@@ -0,0 +1,16 @@
+// Package datastructures holds what the container packages have in common. Each package re-exports these
+// errors under its own name, so errors.Is(err, queue.ErrEmpty) and errors.Is(err, datastructures.ErrEmpty) both work.
+package datastructures
+
+import "errors"
+
+var (
+	// ErrEmpty is returned when removing or peeking at an element of an empty container.
+	ErrEmpty = errors.New("container is empty")
+	// ErrFull is returned when adding to a bounded container that has no room left.
+	ErrFull = errors.New("container is full")
+	// ErrIndexOutOfRange is returned when accessing a position outside of a container.
+	ErrIndexOutOfRange = errors.New("index out of range")
+	// ErrClosed is returned when reading from a closed and drained container, or writing to a closed one.
+	ErrClosed = errors.New("container is closed")
+)
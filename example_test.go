@@ -0,0 +1,45 @@
+package datastructures_test
+
+import (
+	"fmt"
+	"github.com/Shreyas-Adireddy/data_structures"
+	"github.com/Shreyas-Adireddy/data_structures/cqueue"
+	"github.com/Shreyas-Adireddy/data_structures/cstack"
+	"github.com/Shreyas-Adireddy/data_structures/queue"
+	"github.com/Shreyas-Adireddy/data_structures/stack"
+)
+
+// drain works with any FIFO, sequential or concurrent.
+func drain[T any](q datastructures.FIFO[T]) []T {
+	var result []T
+	for !q.IsEmpty() {
+		value, _ := q.Dequeue()
+		result = append(result, value)
+	}
+	return result
+}
+
+func ExampleFIFO() {
+	for _, q := range []datastructures.FIFO[int]{queue.New[int](), cqueue.New[int]()} {
+		q.Enqueue(1)
+		q.Enqueue(2)
+		q.Enqueue(3)
+		fmt.Println(drain(q))
+	}
+	// Output:
+	// [1 2 3]
+	// [1 2 3]
+}
+
+func ExampleLIFO() {
+	for _, s := range []datastructures.LIFO[string]{stack.New[string](), cstack.New[string](), cstack.NewTreiber[string]()} {
+		s.Push("a")
+		s.Push("b")
+		top, _ := s.Pop()
+		fmt.Println(top, s.ToSlice())
+	}
+	// Output:
+	// b [a]
+	// b [a]
+	// b [a]
+}
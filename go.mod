@@ -1,3 +1,3 @@
 module github.com/Shreyas-Adireddy/data_structures
 
-go 1.22
+go 1.23
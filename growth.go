@@ -0,0 +1,29 @@
+package datastructures
+
+// GrowthPolicy returns the capacity a full container of the given capacity grows to. It must return more than
+// capacity, and may return a negative number to signal that the result overflowed an int.
+type GrowthPolicy func(capacity int) int
+
+// SliceGrowth doubles small containers and gradually goes from 2x to 1.25x for large ones, the same formula Go
+// slices use. It is the default policy.
+func SliceGrowth(capacity int) int {
+	if capacity < 256 {
+		return capacity + capacity
+	}
+	return capacity + (capacity+3*256)>>2
+}
+
+// DoublingGrowth always doubles the capacity.
+func DoublingGrowth(capacity int) int {
+	return capacity + capacity
+}
+
+// ChunkGrowth grows the capacity by a fixed number of elements, trading more frequent copies for less unused space.
+func ChunkGrowth(chunk int) GrowthPolicy {
+	if chunk < 1 {
+		panic("growth chunk must be positive")
+	}
+	return func(capacity int) int {
+		return capacity + chunk
+	}
+}
@@ -0,0 +1,58 @@
+package datastructures
+
+// Container is the method set every container in this module has.
+type Container[T any] interface {
+	// Size returns the number of elements in the container.
+	Size() int
+	// IsEmpty checks if the container is empty.
+	IsEmpty() bool
+	// Clear removes all elements from the container.
+	Clear()
+	// ToSlice copies the elements of the container into a new slice, front (or bottom) first.
+	ToSlice() []T
+}
+
+// FIFO is a first-in first-out queue, implemented by queue.Queue, cqueue.ConcurrentQueue and lfqueue.Queue.
+type FIFO[T any] interface {
+	Container[T]
+	// Enqueue adds an element to the rear.
+	Enqueue(value T)
+	// Dequeue removes and returns the front element, or ErrEmpty if there is none. Implementations that can be
+	// closed return ErrClosed instead once they are closed and drained.
+	Dequeue() (T, error)
+	// Front returns the front element without removing it, or ErrEmpty.
+	Front() (T, error)
+	// Back returns the rear element without removing it, or ErrEmpty.
+	Back() (T, error)
+}
+
+// LIFO is a last-in first-out stack, implemented by stack.Stack, cstack.ConcurrentStack and cstack.TreiberStack.
+type LIFO[T any] interface {
+	Container[T]
+	// Push adds an element to the top.
+	Push(value T)
+	// Pop removes and returns the top element, or ErrEmpty if there is none. Implementations that can be closed
+	// return ErrClosed instead once they are closed and drained.
+	Pop() (T, error)
+	// Peek returns the top element without removing it, or ErrEmpty.
+	Peek() (T, error)
+}
+
+// Double is a double-ended queue, implemented by deque.Deque and cdeque.ConcurrentDeque.
+type Double[T any] interface {
+	Container[T]
+	// AddFront adds an element to the front.
+	AddFront(value T)
+	// AddRear adds an element to the rear.
+	AddRear(value T)
+	// PopFront removes and returns the front element, or ErrEmpty if there is none. Implementations that can be
+	// closed return ErrClosed instead once they are closed and drained.
+	PopFront() (T, error)
+	// PopRear removes and returns the rear element, or ErrEmpty if there is none. Implementations that can be
+	// closed return ErrClosed instead once they are closed and drained.
+	PopRear() (T, error)
+	// PeekFront returns the front element without removing it, or ErrEmpty.
+	PeekFront() (T, error)
+	// PeekRear returns the rear element without removing it, or ErrEmpty.
+	PeekRear() (T, error)
+}
@@ -0,0 +1,34 @@
+// Package capacity holds the resize arithmetic shared by the circular array containers.
+package capacity
+
+import "github.com/Shreyas-Adireddy/data_structures"
+
+const maxInt = int(^uint(0) >> 1)
+
+// Grow applies policy to capacity until it holds at least minCapacity elements. A nil policy means
+// datastructures.SliceGrowth.
+func Grow(policy datastructures.GrowthPolicy, capacity, minCapacity int) int {
+	if policy == nil {
+		policy = datastructures.SliceGrowth
+	}
+	newCapacity := max(capacity, 1)
+	for newCapacity < minCapacity {
+		next := policy(newCapacity)
+		if next < 0 {
+			// If overflowed, set it to max int
+			next = maxInt
+		}
+		newCapacity = max(next, newCapacity+1)
+	}
+	return newCapacity
+}
+
+// Shrink halves capacity while size is no more than 1/4 of it, but never below floor. An empty container is
+// not shrunk, since it is likely about to be refilled.
+func Shrink(size, capacity, floor int) int {
+	newCapacity := capacity
+	for size > 0 && size <= newCapacity/4 && newCapacity > floor {
+		newCapacity = max(newCapacity/2, floor)
+	}
+	return newCapacity
+}
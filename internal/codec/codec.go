@@ -0,0 +1,43 @@
+// Package codec implements the encoding methods the containers share. Go can't declare methods on a type from
+// another package, so every container still has its own encoding.go with MarshalJSON, UnmarshalJSON, GobEncode,
+// GobDecode, MarshalBinary and UnmarshalBinary. Each of those is a one-line call into this package, passing the
+// container's elements or its replace method, which is the only part that differs between containers.
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// MarshalJSON encodes values as a JSON array in order.
+func MarshalJSON[T any](values []T) ([]byte, error) {
+	return json.Marshal(values)
+}
+
+// UnmarshalJSON decodes a JSON array and passes its elements to replace in order.
+func UnmarshalJSON[T any](data []byte, replace func([]T) error) error {
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	return replace(values)
+}
+
+// GobEncode gob-encodes values in order.
+func GobEncode[T any](values []T) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(values); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode decodes values encoded by GobEncode and passes them to replace in order.
+func GobDecode[T any](data []byte, replace func([]T) error) error {
+	var values []T
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&values); err != nil {
+		return err
+	}
+	return replace(values)
+}
@@ -0,0 +1,76 @@
+// Package fakeclock provides a datastructures.Clock for tests that only moves when told to.
+package fakeclock
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Clock is a fake datastructures.Clock. Timers returned by After fire once Advance moves the time past them.
+type Clock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []timer
+}
+
+type timer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// New creates a new Clock set to an arbitrary fixed time.
+func New() *Clock {
+	return &Clock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+// Now returns the current fake time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives the fake time once it has advanced by d.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.timers = append(c.timers, timer{c.now.Add(d), ch})
+	return ch
+}
+
+// Advance moves the time forward by d and fires every timer that is due.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.timers = slices.DeleteFunc(c.timers, func(t timer) bool {
+		if t.at.After(c.now) {
+			return false
+		}
+		t.ch <- c.now
+		return true
+	})
+}
+
+// WaitForTimers blocks until at least n timers are pending, so Advance doesn't race with a goroutine about to wait.
+func (c *Clock) WaitForTimers(tb testing.TB, n int) {
+	tb.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		pending := len(c.timers)
+		c.mu.Unlock()
+		if pending >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	tb.Fatalf("Timed out waiting for %d pending timers", n)
+}
@@ -0,0 +1,35 @@
+// Package gctest lets tests check that a container doesn't keep removed elements reachable. It is only imported by
+// tests.
+package gctest
+
+import (
+	"runtime"
+	"time"
+)
+
+// Object is large enough to get its own allocation, so its finalizer runs as soon as it is unreachable.
+type Object struct {
+	buf [1024]byte
+}
+
+// Track returns a new Object and a function that reports whether the garbage collector has freed it. The caller
+// must drop its own references to the Object before asking.
+func Track() (*Object, func() bool) {
+	obj := &Object{}
+	freed := make(chan struct{})
+	runtime.SetFinalizer(obj, func(*Object) {
+		close(freed)
+	})
+	return obj, func() bool {
+		// Finalizers run on their own goroutine after a collection, so give them a few tries
+		for i := 0; i < 20; i++ {
+			runtime.GC()
+			select {
+			case <-freed:
+				return true
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+		return false
+	}
+}
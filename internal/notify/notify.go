@@ -0,0 +1,82 @@
+// Package notify provides the waiter bookkeeping shared by the concurrent containers.
+package notify
+
+import (
+	"context"
+	"sync"
+)
+
+// List is a FIFO of parked goroutines. It is not safe for concurrent use on its own; every method
+// must be called while holding the lock that guards the condition being waited on.
+type List struct {
+	waiters []chan struct{}
+}
+
+// Add registers a new waiter and returns the channel it should block on once the lock is released.
+func (l *List) Add() chan struct{} {
+	ch := make(chan struct{}, 1)
+	l.waiters = append(l.waiters, ch)
+	return ch
+}
+
+// Wait parks the caller until it is signalled or ctx is done, releasing lock while parked. The caller must hold
+// lock, and holds it again when Wait returns. Callers should recheck their condition in a loop.
+func (l *List) Wait(ctx context.Context, lock sync.Locker) error {
+	wake := l.Add()
+	lock.Unlock()
+	select {
+	case <-wake:
+		lock.Lock()
+		return nil
+	case <-ctx.Done():
+		lock.Lock()
+		if !l.Remove(wake) {
+			// We were picked as we gave up, hand the wake-up to the next waiter
+			l.Signal()
+		}
+		return ctx.Err()
+	}
+}
+
+// Remove unregisters a waiter that gave up. It returns false if the waiter was already signalled,
+// in which case the caller should pass the wake-up on with Signal so it isn't lost.
+func (l *List) Remove(ch chan struct{}) bool {
+	for i, w := range l.waiters {
+		if w == ch {
+			l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Signal wakes the longest waiting goroutine, if any.
+func (l *List) Signal() {
+	if len(l.waiters) == 0 {
+		return
+	}
+	ch := l.waiters[0]
+	l.waiters[0] = nil
+	l.waiters = l.waiters[1:]
+	ch <- struct{}{}
+}
+
+// SignalN wakes up to n of the longest waiting goroutines.
+func (l *List) SignalN(n int) {
+	for ; n > 0 && len(l.waiters) > 0; n-- {
+		l.Signal()
+	}
+}
+
+// Broadcast wakes every waiting goroutine.
+func (l *List) Broadcast() {
+	for _, ch := range l.waiters {
+		ch <- struct{}{}
+	}
+	l.waiters = nil
+}
+
+// Len returns the number of parked goroutines.
+func (l *List) Len() int {
+	return len(l.waiters)
+}
@@ -0,0 +1,234 @@
+// Package lfqueue provides a lock-free multi-producer multi-consumer queue, an alternative to cqueue for workloads
+// where many goroutines contend on the same queue.
+//
+// Queue only has the non-blocking part of the cqueue API: datastructures.FIFO (Enqueue, Dequeue, Front, Back, Size,
+// IsEmpty, Clear and ToSlice), EnqueueAll, DequeueN, DequeueInto and the iterators, with the signatures of
+// queue.Queue. It has no bound, so no Cap or TryEnqueue, no DequeueWait or DequeueTimeout, since waiting would need
+// a lock, and no Close or IsClosed. Code that needs those should use cqueue, and code written against
+// datastructures.FIFO can use either.
+package lfqueue
+
+import (
+	"github.com/Shreyas-Adireddy/data_structures"
+	"iter"
+	"sync/atomic"
+)
+
+// ErrEmpty is returned by Dequeue, Front and Back when the queue has no elements.
+var ErrEmpty = datastructures.ErrEmpty
+
+type node[T any] struct {
+	value T // Written once before the node is published, never modified after
+	next  atomic.Pointer[node[T]]
+}
+
+var _ datastructures.FIFO[int] = (*Queue[int])(nil)
+
+// Queue is an unbounded lock-free Michael-Scott queue. head always points at a sentinel node whose successor is
+// the front element, and tail points at the last node or, briefly, the one before it.
+//
+// Nodes are never reused, so the garbage collector rules out the ABA problem the original algorithm needs
+// tagged pointers for. The zero value is not usable, create queues with New.
+type Queue[T any] struct {
+	head atomic.Pointer[node[T]]
+	tail atomic.Pointer[node[T]]
+	size atomic.Int64
+}
+
+// New creates a new Queue.
+func New[T any]() *Queue[T] {
+	q := &Queue[T]{}
+	sentinel := &node[T]{}
+	q.head.Store(sentinel)
+	q.tail.Store(sentinel)
+	return q
+}
+
+// Enqueue adds an element to the rear of the queue.
+func (q *Queue[T]) Enqueue(value T) {
+	n := &node[T]{value: value}
+	q.link(n, n, 1)
+}
+
+// EnqueueAll appends values to the rear of the queue in order. They are linked in with a single CAS, so they stay
+// contiguous even when other goroutines enqueue at the same time.
+func (q *Queue[T]) EnqueueAll(values ...T) {
+	if len(values) == 0 {
+		return
+	}
+	first := &node[T]{value: values[0]}
+	last := first
+	for _, value := range values[1:] {
+		n := &node[T]{value: value}
+		last.next.Store(n)
+		last = n
+	}
+	q.link(first, last, len(values))
+}
+
+// link appends the chain of count nodes from first to last after the current tail.
+func (q *Queue[T]) link(first, last *node[T], count int) {
+	for {
+		tail := q.tail.Load()
+		next := tail.next.Load()
+		if next != nil {
+			// tail is lagging behind, help the enqueuer that linked next before retrying
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		if tail.next.CompareAndSwap(nil, first) {
+			// If another goroutine already helped tail onto the chain, it moves on to last the same way
+			q.tail.CompareAndSwap(tail, last)
+			q.size.Add(int64(count))
+			return
+		}
+	}
+}
+
+// Dequeue removes and returns an element from the front of the queue.
+func (q *Queue[T]) Dequeue() (T, error) {
+	for {
+		head := q.head.Load()
+		tail := q.tail.Load()
+		next := head.next.Load()
+		if next == nil {
+			var zero T
+			return zero, ErrEmpty
+		}
+		if head == tail {
+			// tail is lagging behind the node we're about to dequeue, move it on first
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		if q.head.CompareAndSwap(head, next) {
+			// next is the new sentinel. Its value stays reachable until the following Dequeue, since concurrent
+			// readers may still be looking at it.
+			q.size.Add(-1)
+			return next.value, nil
+		}
+	}
+}
+
+// DequeueN removes up to n elements from the front of the queue and returns them in order. Under concurrent use,
+// other consumers may take elements in between, so the result isn't necessarily contiguous.
+func (q *Queue[T]) DequeueN(n int) []T {
+	result := make([]T, 0, max(min(n, q.Size()), 0))
+	for len(result) < n {
+		value, err := q.Dequeue()
+		if err != nil {
+			break
+		}
+		result = append(result, value)
+	}
+	return result
+}
+
+// DequeueInto removes elements from the front of the queue into dst until either is exhausted, and returns how many
+// were removed.
+func (q *Queue[T]) DequeueInto(dst []T) int {
+	for i := range dst {
+		value, err := q.Dequeue()
+		if err != nil {
+			return i
+		}
+		dst[i] = value
+	}
+	return len(dst)
+}
+
+// Front returns the front element of the queue without removing it.
+func (q *Queue[T]) Front() (T, error) {
+	next := q.head.Load().next.Load()
+	if next == nil {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return next.value, nil
+}
+
+// Back returns the rear element of the queue without removing it.
+func (q *Queue[T]) Back() (T, error) {
+	head := q.head.Load()
+	last := q.tail.Load()
+	for next := last.next.Load(); next != nil; next = last.next.Load() {
+		last = next
+	}
+	if last == head {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return last.value, nil
+}
+
+// Size returns the number of elements in the queue. Under concurrent use it is only a snapshot.
+func (q *Queue[T]) Size() int {
+	// A Dequeue can briefly be counted before the Enqueue it removed
+	return max(int(q.size.Load()), 0)
+}
+
+// IsEmpty checks if the queue is empty.
+func (q *Queue[T]) IsEmpty() bool {
+	return q.head.Load().next.Load() == nil
+}
+
+// Clear removes all elements from the queue by dequeuing them. Elements enqueued concurrently may or may not be
+// removed.
+func (q *Queue[T]) Clear() {
+	for {
+		if _, err := q.Dequeue(); err != nil {
+			return
+		}
+	}
+}
+
+// ToSlice converts the queue to a slice and returns it. Under concurrent use it reflects the elements between the
+// front and the rear at some point during the call.
+func (q *Queue[T]) ToSlice() []T {
+	var result []T
+	for n := q.head.Load().next.Load(); n != nil; n = n.next.Load() {
+		result = append(result, n.value)
+	}
+	if result == nil {
+		result = []T{}
+	}
+	return result
+}
+
+// All returns an iterator over the elements of the queue from front to rear. It walks the nodes without copying,
+// so elements enqueued during iteration may be visited.
+func (q *Queue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for n := q.head.Load().next.Load(); n != nil; n = n.next.Load() {
+			if !yield(n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over a snapshot of the queue from rear to front. The nodes only link forward, so it
+// copies the elements first.
+func (q *Queue[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		values := q.ToSlice()
+		for i := len(values) - 1; i >= 0; i-- {
+			if !yield(values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns an iterator over the positions and elements of the queue, where position 0 is the front. Like
+// All, it walks the nodes without copying.
+func (q *Queue[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for value := range q.All() {
+			if !yield(i, value) {
+				return
+			}
+			i++
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package lfqueue
+
+import (
+	"errors"
+	"github.com/Shreyas-Adireddy/data_structures"
+	"github.com/Shreyas-Adireddy/data_structures/cqueue"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestQueue(t *testing.T) {
+	q := New[int]()
+	if _, err := q.Dequeue(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+	if _, err := q.Back(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		q.Enqueue(i)
+	}
+	if front, err := q.Front(); err != nil || front != 0 {
+		t.Errorf("Front returned unexpected value: %v, expected: %v, error: %v", front, 0, err)
+	}
+	if back, err := q.Back(); err != nil || back != 4 {
+		t.Errorf("Back returned unexpected value: %v, expected: %v, error: %v", back, 4, err)
+	}
+	if q.Size() != 5 {
+		t.Errorf("Expected size to be 5, got %d", q.Size())
+	}
+	if expected := []int{0, 1, 2, 3, 4}; !slices.Equal(q.ToSlice(), expected) || !slices.Equal(slices.Collect(q.All()), expected) {
+		t.Errorf("Expected %v, got %v", expected, q.ToSlice())
+	}
+	for i := 0; i < 3; i++ {
+		if val, err := q.Dequeue(); err != nil || val != i {
+			t.Errorf("Dequeue returned unexpected value: %v, expected: %v, error: %v", val, i, err)
+		}
+	}
+	q.Clear()
+	if !q.IsEmpty() || q.Size() != 0 {
+		t.Errorf("Expected queue to be empty after Clear, got size %d", q.Size())
+	}
+}
+
+// Code written against datastructures.FIFO can take either queue
+func TestFIFO(t *testing.T) {
+	for name, q := range map[string]datastructures.FIFO[int]{"lfqueue": New[int](), "cqueue": cqueue.New[int]()} {
+		q.Enqueue(1)
+		q.Enqueue(2)
+		if val, err := q.Dequeue(); err != nil || val != 1 {
+			t.Errorf("%s: Expected Dequeue to return 1, got %v (error: %v)", name, val, err)
+		}
+		if !slices.Equal(q.ToSlice(), []int{2}) {
+			t.Errorf("%s: Expected [2], got %v", name, q.ToSlice())
+		}
+	}
+}
+
+func TestIterators(t *testing.T) {
+	q := New[int]()
+	q.EnqueueAll(0, 1, 2)
+	if got, expected := slices.Collect(q.Backward()), []int{2, 1, 0}; !slices.Equal(got, expected) {
+		t.Errorf("Backward returned %v, expected %v", got, expected)
+	}
+	for i, val := range q.Enumerate() {
+		if val != i {
+			t.Errorf("Enumerate returned %v at %v, expected %v", val, i, i)
+		}
+	}
+}
+
+func TestBatchOperations(t *testing.T) {
+	q := New[int]()
+	q.EnqueueAll()
+	if !q.IsEmpty() {
+		t.Errorf("Expected EnqueueAll with no values to leave the queue empty, got %v", q.ToSlice())
+	}
+	q.EnqueueAll(0, 1, 2, 3, 4)
+	q.Enqueue(5)
+	if back, _ := q.Back(); back != 5 || q.Size() != 6 {
+		t.Errorf("Expected back 5 and size 6, got %v and %d", back, q.Size())
+	}
+	if got := q.DequeueN(2); !slices.Equal(got, []int{0, 1}) {
+		t.Errorf("DequeueN returned %v, expected %v", got, []int{0, 1})
+	}
+	dst := make([]int, 3)
+	if n := q.DequeueInto(dst); n != 3 || !slices.Equal(dst, []int{2, 3, 4}) {
+		t.Errorf("DequeueInto returned %v (%d), expected %v", dst, n, []int{2, 3, 4})
+	}
+	if got := q.DequeueN(5); !slices.Equal(got, []int{5}) {
+		t.Errorf("DequeueN returned %v, expected %v", got, []int{5})
+	}
+	if n := q.DequeueInto(dst); n != 0 {
+		t.Errorf("Expected DequeueInto on an empty queue to return 0, got %d", n)
+	}
+
+	// A batch stays contiguous while other goroutines enqueue
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				q.EnqueueAll(g*1000+i*3, g*1000+i*3+1, g*1000+i*3+2)
+			}
+		}(g)
+	}
+	wg.Wait()
+	values := q.ToSlice()
+	if len(values) != 1200 || q.Size() != 1200 {
+		t.Fatalf("Expected 1200 elements, got %d (size %d)", len(values), q.Size())
+	}
+	for i := 0; i < len(values); i += 3 {
+		if values[i+1] != values[i]+1 || values[i+2] != values[i]+2 {
+			t.Fatalf("Expected batches to stay contiguous, got %v", values[i:i+3])
+		}
+	}
+}
+
+func TestConcurrentProducersAndConsumers(t *testing.T) {
+	const producers, consumers, perProducer = 8, 8, 2000
+	q := New[int]()
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				q.Enqueue(p*perProducer + i)
+			}
+		}(p)
+	}
+
+	results := make([][]int, consumers)
+	var remaining atomic.Int64
+	remaining.Store(producers * perProducer)
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			for remaining.Load() > 0 {
+				val, err := q.Dequeue()
+				if err != nil {
+					continue
+				}
+				results[c] = append(results[c], val)
+				remaining.Add(-1)
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	seen := make([]bool, producers*perProducer)
+	for _, r := range results {
+		// Each consumer must see every producer's values in the order they were enqueued
+		last := make(map[int]int)
+		for _, val := range r {
+			if seen[val] {
+				t.Fatalf("Value %d was dequeued twice", val)
+			}
+			seen[val] = true
+			if prev, ok := last[val/perProducer]; ok && prev > val {
+				t.Fatalf("Values from producer %d were dequeued out of order: %d after %d", val/perProducer, val, prev)
+			}
+			last[val/perProducer] = val
+		}
+	}
+	if !q.IsEmpty() {
+		t.Errorf("Expected queue to be empty, got size %d", q.Size())
+	}
+}
+
+// benchmarkQueue runs an Enqueue followed by a Dequeue on every iteration across GOMAXPROCS*parallelism goroutines.
+func benchmarkQueue(b *testing.B, enqueue func(int), dequeue func()) {
+	for _, parallelism := range []int{1, 4, 32} {
+		b.Run("parallelism="+strconv.Itoa(parallelism), func(b *testing.B) {
+			b.SetParallelism(parallelism)
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					enqueue(1)
+					dequeue()
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkLockFree(b *testing.B) {
+	q := New[int]()
+	benchmarkQueue(b, q.Enqueue, func() { _, _ = q.Dequeue() })
+}
+
+func BenchmarkCQueue(b *testing.B) {
+	cq := cqueue.New[int]()
+	benchmarkQueue(b, cq.Enqueue, func() { _, _ = cq.Dequeue() })
+}
+
+func BenchmarkChannel(b *testing.B) {
+	ch := make(chan int, 1<<16)
+	benchmarkQueue(b, func(v int) { ch <- v }, func() { <-ch })
+}
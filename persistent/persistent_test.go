@@ -0,0 +1,178 @@
+package persistent
+
+import (
+	"errors"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestStack(t *testing.T) {
+	var empty Stack[int]
+	if _, _, err := empty.Pop(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+
+	s1 := NewStack[int]().Push(1).Push(2)
+	s2 := s1.Push(3)
+	top, s3, err := s2.Pop()
+	if err != nil || top != 3 {
+		t.Errorf("Expected Pop to return 3, got %v (error: %v)", top, err)
+	}
+	s4 := s3.Push(4)
+
+	// Every version keeps its own contents
+	for _, tc := range []struct {
+		s        Stack[int]
+		expected []int
+	}{
+		{empty, []int{}},
+		{s1, []int{1, 2}},
+		{s2, []int{1, 2, 3}},
+		{s3, []int{1, 2}},
+		{s4, []int{1, 2, 4}},
+	} {
+		if got := tc.s.ToSlice(); !slices.Equal(got, tc.expected) || tc.s.Size() != len(tc.expected) {
+			t.Errorf("Expected %v, got %v with size %d", tc.expected, got, tc.s.Size())
+		}
+	}
+	if got := slices.Collect(s4.Backward()); !slices.Equal(got, []int{4, 2, 1}) {
+		t.Errorf("Expected Backward to yield [4 2 1], got %v", got)
+	}
+	if got := slices.Collect(s4.All()); !slices.Equal(got, []int{1, 2, 4}) {
+		t.Errorf("Expected All to yield [1 2 4], got %v", got)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	var empty Queue[int]
+	if _, _, err := empty.Dequeue(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+	if _, err := empty.Back(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+
+	q := NewQueue[int]()
+	var versions []Queue[int]
+	for i := 0; i < 10; i++ {
+		q = q.Enqueue(i)
+		versions = append(versions, q)
+	}
+	val, q5, err := versions[5].Dequeue()
+	if err != nil || val != 0 {
+		t.Errorf("Expected Dequeue to return 0, got %v (error: %v)", val, err)
+	}
+	q5 = q5.Enqueue(100)
+
+	for i, v := range versions {
+		expected := make([]int, i+1)
+		for j := range expected {
+			expected[j] = j
+		}
+		if got := v.ToSlice(); !slices.Equal(got, expected) {
+			t.Errorf("Expected version %d to hold %v, got %v", i, expected, got)
+		}
+		if back, _ := v.Back(); back != i {
+			t.Errorf("Expected Back of version %d to be %d, got %v", i, i, back)
+		}
+	}
+	if got, expected := q5.ToSlice(), []int{1, 2, 3, 4, 5, 100}; !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if front, _ := q5.Front(); front != 1 {
+		t.Errorf("Expected Front to return 1, got %v", front)
+	}
+}
+
+// Checks the queue against a slice through a random interleaving, including operations on old versions
+func TestQueueOperations(t *testing.T) {
+	type version struct {
+		q        Queue[int]
+		expected []int
+	}
+	versions := []version{{}}
+	for i := 0; i < 2000; i++ {
+		v := versions[(i*7919)%len(versions)]
+		if i%3 == 2 {
+			val, q, err := v.q.Dequeue()
+			if len(v.expected) == 0 {
+				if !errors.Is(err, ErrEmpty) {
+					t.Fatalf("Expected ErrEmpty, got %v", err)
+				}
+				continue
+			}
+			if err != nil || val != v.expected[0] {
+				t.Fatalf("Expected Dequeue to return %d, got %v (error: %v)", v.expected[0], val, err)
+			}
+			versions = append(versions, version{q, v.expected[1:]})
+		} else {
+			versions = append(versions, version{v.q.Enqueue(i), append(slices.Clip(v.expected), i)})
+		}
+	}
+	for i, v := range versions {
+		if got := v.q.ToSlice(); !slices.Equal(got, v.expected) || v.q.Size() != len(v.expected) {
+			t.Fatalf("Expected version %d to hold %v, got %v", i, v.expected, got)
+		}
+	}
+}
+
+// mallocs returns how many heap allocations f makes, a stand-in for how much work it does
+func mallocs(f func()) uint64 {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	f()
+	runtime.ReadMemStats(&after)
+	return after.Mallocs - before.Mallocs
+}
+
+// The work of every single operation is bounded by a constant, no matter the size of the queue
+func TestQueueWorstCase(t *testing.T) {
+	const n = 1 << 12
+	const bound = 16
+	q := NewQueue[int]()
+	for i := 0; i < n; i++ {
+		if m := mallocs(func() { q = q.Enqueue(i) }); m > bound {
+			t.Fatalf("Enqueue %d took %d allocations, expected at most %d", i, m, bound)
+		}
+	}
+	for i := 0; i < n; i++ {
+		if m := mallocs(func() { _, q, _ = q.Dequeue() }); m > bound {
+			t.Fatalf("Dequeue %d took %d allocations, expected at most %d", i, m, bound)
+		}
+	}
+}
+
+// Reusing an old version is where a plain two-stack queue loses its amortized bound: every Dequeue on a version
+// whose front is empty reverses the whole rear again. Here the reversal is shared by every caller
+func TestQueuePersistentReuse(t *testing.T) {
+	const n = 1 << 12
+	q := NewQueue[int]()
+	for i := 0; i < n; i++ {
+		q = q.Enqueue(i)
+	}
+	total := mallocs(func() {
+		for i := 0; i < 1000; i++ {
+			if val, _, _ := q.Dequeue(); val != 0 {
+				t.Fatalf("Expected Dequeue to return 0, got %v", val)
+			}
+		}
+	})
+	if total > 1000*16 {
+		t.Errorf("Expected repeated Dequeue on the same version to stay O(1), took %d allocations", total)
+	}
+}
+
+func TestStackWorstCase(t *testing.T) {
+	s := NewStack[int]()
+	for i := 0; i < 1000; i++ {
+		if m := mallocs(func() { s = s.Push(i) }); m > 1 {
+			t.Fatalf("Push took %d allocations, expected 1", m)
+		}
+	}
+	for !s.IsEmpty() {
+		if m := mallocs(func() { _, s, _ = s.Pop() }); m > 0 {
+			t.Fatalf("Pop took %d allocations, expected none", m)
+		}
+	}
+}
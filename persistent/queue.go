@@ -0,0 +1,146 @@
+package persistent
+
+import (
+	"iter"
+	"sync"
+)
+
+// stream is a lazily evaluated linked list. Each cell is computed at most once and then shared by every version
+// of the queue holding it. A nil stream is empty.
+type stream[T any] struct {
+	once  sync.Once
+	force func() *cell[T] // Computes the cell, nil once it has run or if the cell was known up front
+	cell  *cell[T]
+}
+
+type cell[T any] struct {
+	value T
+	next  *stream[T]
+}
+
+// get evaluates the stream if needed and returns its first cell, or nil if it is empty.
+func (s *stream[T]) get() *cell[T] {
+	if s == nil {
+		return nil
+	}
+	s.once.Do(func() {
+		if s.force != nil {
+			s.cell = s.force()
+			s.force = nil
+		}
+	})
+	return s.cell
+}
+
+// rotate lazily computes front followed by the reverse of rear, appended to acc. rear must have exactly one element
+// more than front. Each cell takes O(1) to evaluate, since it only walks one step of front and rear.
+func rotate[T any](front *stream[T], rear *node[T], acc *stream[T]) *stream[T] {
+	return &stream[T]{force: func() *cell[T] {
+		c := front.get()
+		if c == nil {
+			return &cell[T]{value: rear.value, next: acc}
+		}
+		reversed := &stream[T]{cell: &cell[T]{value: rear.value, next: acc}}
+		return &cell[T]{value: c.value, next: rotate(c.next, rear.next, reversed)}
+	}}
+}
+
+// Queue is an immutable queue with O(1) worst case Enqueue and Dequeue, even when old versions are reused (Okasaki's
+// real-time queue). Elements are enqueued onto a persistent Stack, which is lazily reversed onto the front once it
+// grows longer than the front, and every operation evaluates one more step of that reversal so none of them ever
+// pays for all of it. The zero value is an empty queue.
+type Queue[T any] struct {
+	front    *stream[T]
+	frontLen int
+	rear     Stack[T]
+	schedule *stream[T] // Part of front not evaluated yet, one step of it is forced per operation
+	back     T
+}
+
+// NewQueue creates a new empty Queue.
+func NewQueue[T any]() Queue[T] {
+	return Queue[T]{}
+}
+
+// Enqueue returns a new version of the queue with value at the rear.
+func (q Queue[T]) Enqueue(value T) Queue[T] {
+	result := makeQueue(q.front, q.frontLen, q.rear.Push(value), q.schedule)
+	result.back = value
+	return result
+}
+
+// Dequeue returns the front element and a new version of the queue without it.
+func (q Queue[T]) Dequeue() (T, Queue[T], error) {
+	c := q.front.get()
+	if c == nil {
+		var zero T
+		return zero, q, ErrEmpty
+	}
+	result := makeQueue(c.next, q.frontLen-1, q.rear, q.schedule)
+	result.back = q.back
+	return c.value, result, nil
+}
+
+// makeQueue evaluates one step of the schedule, or starts reversing rear onto front once rear is longer.
+func makeQueue[T any](front *stream[T], frontLen int, rear Stack[T], schedule *stream[T]) Queue[T] {
+	if c := schedule.get(); c != nil {
+		return Queue[T]{front: front, frontLen: frontLen, rear: rear, schedule: c.next}
+	}
+	front = rotate(front, rear.top, nil)
+	return Queue[T]{front: front, frontLen: frontLen + rear.size, schedule: front}
+}
+
+// Front returns the front element of the queue.
+func (q Queue[T]) Front() (T, error) {
+	c := q.front.get()
+	if c == nil {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return c.value, nil
+}
+
+// Back returns the rear element of the queue.
+func (q Queue[T]) Back() (T, error) {
+	if q.IsEmpty() {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return q.back, nil
+}
+
+// Size returns the number of elements in the queue.
+func (q Queue[T]) Size() int {
+	return q.frontLen + q.rear.size
+}
+
+// IsEmpty checks if the queue is empty.
+func (q Queue[T]) IsEmpty() bool {
+	return q.Size() == 0
+}
+
+// ToSlice returns the elements of the queue from front to rear.
+func (q Queue[T]) ToSlice() []T {
+	result := make([]T, 0, q.Size())
+	for value := range q.All() {
+		result = append(result, value)
+	}
+	return result
+}
+
+// All returns an iterator over the elements of the queue from front to rear. It evaluates whatever part of the front
+// is still pending, which the versions sharing it then don't have to.
+func (q Queue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for c := q.front.get(); c != nil; c = c.next.get() {
+			if !yield(c.value) {
+				return
+			}
+		}
+		for _, value := range q.rear.ToSlice() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
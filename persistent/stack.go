@@ -0,0 +1,96 @@
+// Package persistent provides immutable containers whose operations return a new version and leave the old one
+// intact, sharing structure between versions so that keeping a snapshot costs O(1).
+package persistent
+
+import (
+	"github.com/Shreyas-Adireddy/data_structures"
+	"iter"
+)
+
+// ErrEmpty is returned by Pop, Peek, Dequeue and Front when the container has no elements.
+var ErrEmpty = datastructures.ErrEmpty
+
+type node[T any] struct {
+	value T
+	next  *node[T]
+}
+
+// Stack is an immutable stack built as a linked list, where every version shares the elements below its top with
+// the version it was pushed onto. The zero value is an empty stack. Every operation is O(1) worst case, apart from
+// ToSlice and the iterators.
+type Stack[T any] struct {
+	top  *node[T]
+	size int
+}
+
+// NewStack creates a new empty Stack.
+func NewStack[T any]() Stack[T] {
+	return Stack[T]{}
+}
+
+// Push returns a new version of the stack with element on top.
+func (s Stack[T]) Push(element T) Stack[T] {
+	return Stack[T]{top: &node[T]{value: element, next: s.top}, size: s.size + 1}
+}
+
+// Pop returns the top element and a new version of the stack without it.
+func (s Stack[T]) Pop() (T, Stack[T], error) {
+	if s.top == nil {
+		var zero T
+		return zero, s, ErrEmpty
+	}
+	return s.top.value, Stack[T]{top: s.top.next, size: s.size - 1}, nil
+}
+
+// Peek returns the top element of the stack.
+func (s Stack[T]) Peek() (T, error) {
+	if s.top == nil {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return s.top.value, nil
+}
+
+// Size returns the number of elements in the stack.
+func (s Stack[T]) Size() int {
+	return s.size
+}
+
+// IsEmpty checks if the stack is empty.
+func (s Stack[T]) IsEmpty() bool {
+	return s.size == 0
+}
+
+// ToSlice returns the elements of the stack from bottom to top.
+func (s Stack[T]) ToSlice() []T {
+	result := make([]T, s.size)
+	i := s.size - 1
+	for n := s.top; n != nil; n = n.next {
+		result[i] = n.value
+		i--
+	}
+	return result
+}
+
+// All returns an iterator over the elements of the stack from bottom to top. The list only links downwards, so it
+// iterates over a copy.
+func (s Stack[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range s.ToSlice() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the elements of the stack from top to bottom.
+func (s Stack[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for n := s.top; n != nil; n = n.next {
+			if !yield(n.value) {
+				return
+			}
+		}
+	}
+}
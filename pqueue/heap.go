@@ -0,0 +1,86 @@
+package pqueue
+
+// heap is the binary min-heap over a slice shared by PriorityQueue and Indexed. moved is called whenever an element
+// lands at a new position, so the owner can keep track of where its elements are.
+type heap[E any] struct {
+	items []E
+	less  func(a, b E) bool
+	moved func(e E, i int)
+}
+
+// init restores the heap order of every element in O(n).
+func (h *heap[E]) init() {
+	for i, e := range h.items {
+		h.moved(e, i)
+	}
+	for i := len(h.items)/2 - 1; i >= 0; i-- {
+		h.down(i)
+	}
+}
+
+func (h *heap[E]) push(e E) {
+	h.items = append(h.items, e)
+	h.moved(e, len(h.items)-1)
+	h.up(len(h.items) - 1)
+}
+
+// removeAt removes the element at position i by swapping the last element into its place.
+func (h *heap[E]) removeAt(i int) E {
+	e := h.items[i]
+	last := len(h.items) - 1
+	if i != last {
+		h.swap(i, last)
+	}
+	// Clear the slot so the heap doesn't keep the element reachable
+	var zero E
+	h.items[last] = zero
+	h.items = h.items[:last]
+	if i != last {
+		h.fix(i)
+	}
+	return e
+}
+
+// fix moves the element at i up or down until the heap order holds again.
+func (h *heap[E]) fix(i int) {
+	if !h.down(i) {
+		h.up(i)
+	}
+}
+
+func (h *heap[E]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.items[i], h.items[parent]) {
+			return
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+// down moves the element at i towards the leaves and reports whether it moved.
+func (h *heap[E]) down(i int) bool {
+	start := i
+	for {
+		smallest := i
+		left, right := 2*i+1, 2*i+2
+		if left < len(h.items) && h.less(h.items[left], h.items[smallest]) {
+			smallest = left
+		}
+		if right < len(h.items) && h.less(h.items[right], h.items[smallest]) {
+			smallest = right
+		}
+		if smallest == i {
+			return i != start
+		}
+		h.swap(i, smallest)
+		i = smallest
+	}
+}
+
+func (h *heap[E]) swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.moved(h.items[i], i)
+	h.moved(h.items[j], j)
+}
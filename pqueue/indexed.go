@@ -0,0 +1,186 @@
+package pqueue
+
+import (
+	"cmp"
+	"errors"
+	"github.com/Shreyas-Adireddy/data_structures"
+)
+
+var (
+	// ErrNotFound is returned when an ID is not in the indexed priority queue.
+	ErrNotFound = errors.New("id is not in the priority queue")
+	// ErrDuplicate is returned by Push when an ID is already in the indexed priority queue.
+	ErrDuplicate = errors.New("id is already in the priority queue")
+	// ErrWrongDirection is returned by DecreaseKey and IncreaseKey when the new priority moves the other way.
+	ErrWrongDirection = errors.New("new priority moves the id the other way")
+)
+
+var _ datastructures.Container[string] = (*Indexed[string, int])(nil)
+
+type entry[K comparable, P any] struct {
+	id       K
+	priority P
+}
+
+// Indexed is a binary heap of IDs ordered by their priorities, with a map from each ID to its position in the heap
+// so priorities can be changed in O(log n). The ID whose priority less reports true against every other one is
+// popped first.
+type Indexed[K comparable, P any] struct {
+	heap  heap[entry[K, P]]
+	index map[K]int
+	less  func(a, b P) bool
+}
+
+// NewIndexed creates a new Indexed priority queue ordered by less.
+func NewIndexed[K comparable, P any](less func(a, b P) bool) *Indexed[K, P] {
+	index := make(map[K]int)
+	return &Indexed[K, P]{
+		heap: heap[entry[K, P]]{
+			less:  func(a, b entry[K, P]) bool { return less(a.priority, b.priority) },
+			moved: func(e entry[K, P], i int) { index[e.id] = i },
+		},
+		index: index,
+		less:  less,
+	}
+}
+
+// NewIndexedMin creates a new Indexed priority queue that pops the ID with the smallest priority first.
+func NewIndexedMin[K comparable, P cmp.Ordered]() *Indexed[K, P] {
+	return NewIndexed[K](cmp.Less[P])
+}
+
+// NewIndexedMax creates a new Indexed priority queue that pops the ID with the largest priority first. DecreaseKey
+// and IncreaseKey follow the queue's order, so on a max queue DecreaseKey takes a larger priority.
+func NewIndexedMax[K comparable, P cmp.Ordered]() *Indexed[K, P] {
+	return NewIndexed[K](func(a, b P) bool { return a > b })
+}
+
+// Push adds id with the given priority. Returns ErrDuplicate if id is already in the priority queue.
+func (pq *Indexed[K, P]) Push(id K, priority P) error {
+	if _, ok := pq.index[id]; ok {
+		return ErrDuplicate
+	}
+	pq.heap.push(entry[K, P]{id, priority})
+	return nil
+}
+
+// Pop removes and returns the ID with the highest priority, and its priority.
+func (pq *Indexed[K, P]) Pop() (K, P, error) {
+	if len(pq.heap.items) == 0 {
+		var id K
+		var priority P
+		return id, priority, ErrEmpty
+	}
+	e := pq.removeAt(0)
+	return e.id, e.priority, nil
+}
+
+// Peek returns the ID with the highest priority, and its priority, without removing it.
+func (pq *Indexed[K, P]) Peek() (K, P, error) {
+	if len(pq.heap.items) == 0 {
+		var id K
+		var priority P
+		return id, priority, ErrEmpty
+	}
+	return pq.heap.items[0].id, pq.heap.items[0].priority, nil
+}
+
+// Priority returns the priority of id, and whether it is in the priority queue.
+func (pq *Indexed[K, P]) Priority(id K) (P, bool) {
+	i, ok := pq.index[id]
+	if !ok {
+		var zero P
+		return zero, false
+	}
+	return pq.heap.items[i].priority, true
+}
+
+// Contains checks if id is in the priority queue.
+func (pq *Indexed[K, P]) Contains(id K) bool {
+	_, ok := pq.index[id]
+	return ok
+}
+
+// DecreaseKey changes the priority of id to one that less orders no later than the current one, moving it towards
+// the front. Returns ErrWrongDirection if priority would move it towards the back.
+func (pq *Indexed[K, P]) DecreaseKey(id K, priority P) error {
+	i, ok := pq.index[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if pq.less(pq.heap.items[i].priority, priority) {
+		return ErrWrongDirection
+	}
+	pq.heap.items[i].priority = priority
+	pq.heap.up(i)
+	return nil
+}
+
+// IncreaseKey changes the priority of id to one that less orders no earlier than the current one, moving it towards
+// the back. Returns ErrWrongDirection if priority would move it towards the front.
+func (pq *Indexed[K, P]) IncreaseKey(id K, priority P) error {
+	i, ok := pq.index[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if pq.less(priority, pq.heap.items[i].priority) {
+		return ErrWrongDirection
+	}
+	pq.heap.items[i].priority = priority
+	pq.heap.down(i)
+	return nil
+}
+
+// Update changes the priority of id in either direction.
+func (pq *Indexed[K, P]) Update(id K, priority P) error {
+	i, ok := pq.index[id]
+	if !ok {
+		return ErrNotFound
+	}
+	pq.heap.items[i].priority = priority
+	pq.heap.fix(i)
+	return nil
+}
+
+// Remove removes id from the priority queue and returns its priority.
+func (pq *Indexed[K, P]) Remove(id K) (P, error) {
+	i, ok := pq.index[id]
+	if !ok {
+		var zero P
+		return zero, ErrNotFound
+	}
+	return pq.removeAt(i).priority, nil
+}
+
+// Size returns the number of IDs in the priority queue.
+func (pq *Indexed[K, P]) Size() int {
+	return len(pq.heap.items)
+}
+
+// IsEmpty checks if the priority queue is empty.
+func (pq *Indexed[K, P]) IsEmpty() bool {
+	return len(pq.heap.items) == 0
+}
+
+// Clear removes all IDs from the priority queue.
+func (pq *Indexed[K, P]) Clear() {
+	pq.heap.items = nil
+	clear(pq.index)
+}
+
+// ToSlice returns the IDs in the priority queue in heap order, which is not sorted beyond the first ID having the
+// highest priority.
+func (pq *Indexed[K, P]) ToSlice() []K {
+	result := make([]K, len(pq.heap.items))
+	for i, e := range pq.heap.items {
+		result[i] = e.id
+	}
+	return result
+}
+
+// removeAt removes the entry at heap position i and forgets its ID.
+func (pq *Indexed[K, P]) removeAt(i int) entry[K, P] {
+	e := pq.heap.removeAt(i)
+	delete(pq.index, e.id)
+	return e
+}
@@ -0,0 +1,159 @@
+package pqueue
+
+import (
+	"cmp"
+	"errors"
+	"github.com/Shreyas-Adireddy/data_structures"
+)
+
+var (
+	// ErrEmpty is returned by Pop and Peek when the priority queue has no elements.
+	ErrEmpty = datastructures.ErrEmpty
+	// ErrInvalidHandle is returned when a handle was already popped or removed, or belongs to another queue.
+	ErrInvalidHandle = errors.New("handle is not in the priority queue")
+)
+
+var _ datastructures.Container[int] = (*PriorityQueue[int])(nil)
+
+// Handle refers to an element pushed onto a PriorityQueue, so it can be updated or removed later.
+type Handle[T any] struct {
+	value T
+	index int // Position in the heap, -1 once the element left the queue
+}
+
+// Value returns the element the handle refers to. It reads the handle without synchronization, so with a
+// cpqueue.ConcurrentPriorityQueue use its Value method instead.
+func (h *Handle[T]) Value() T {
+	return h.value
+}
+
+// PriorityQueue is a binary min-heap over a slice, ordered by a less function. The element for which less reports
+// true against every other element is popped first; pass a "greater" function for a max-heap.
+type PriorityQueue[T any] struct {
+	heap heap[*Handle[T]]
+}
+
+// New creates a new PriorityQueue ordered by less.
+func New[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{
+		heap: heap[*Handle[T]]{
+			less:  func(a, b *Handle[T]) bool { return less(a.value, b.value) },
+			moved: func(h *Handle[T], i int) { h.index = i },
+		},
+	}
+}
+
+// NewOrdered creates a new PriorityQueue that pops the smallest element first.
+func NewOrdered[T cmp.Ordered]() *PriorityQueue[T] {
+	return New(cmp.Less[T])
+}
+
+// FromSlice creates a new PriorityQueue ordered by less holding values, in O(n) rather than O(n log n) for pushing
+// them one at a time.
+func FromSlice[T any](values []T, less func(a, b T) bool) *PriorityQueue[T] {
+	pq := New(less)
+	pq.heap.items = make([]*Handle[T], len(values))
+	for i, value := range values {
+		pq.heap.items[i] = &Handle[T]{value: value}
+	}
+	pq.heap.init()
+	return pq
+}
+
+// Push adds an element to the priority queue and returns a handle to it.
+func (pq *PriorityQueue[T]) Push(value T) *Handle[T] {
+	h := &Handle[T]{value: value}
+	pq.heap.push(h)
+	return h
+}
+
+// Pop removes and returns the element with the highest priority.
+func (pq *PriorityQueue[T]) Pop() (T, error) {
+	if len(pq.heap.items) == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return pq.removeAt(0), nil
+}
+
+// Peek returns the element with the highest priority without removing it.
+func (pq *PriorityQueue[T]) Peek() (T, error) {
+	if len(pq.heap.items) == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return pq.heap.items[0].value, nil
+}
+
+// Update replaces the element h refers to and restores the heap order.
+func (pq *PriorityQueue[T]) Update(h *Handle[T], value T) error {
+	if !pq.contains(h) {
+		return ErrInvalidHandle
+	}
+	h.value = value
+	pq.heap.fix(h.index)
+	return nil
+}
+
+// Fix restores the heap order after the priority of the element h refers to changed in place, for example
+// because it is a pointer whose fields were modified.
+func (pq *PriorityQueue[T]) Fix(h *Handle[T]) error {
+	if !pq.contains(h) {
+		return ErrInvalidHandle
+	}
+	pq.heap.fix(h.index)
+	return nil
+}
+
+// Remove removes and returns the element h refers to.
+func (pq *PriorityQueue[T]) Remove(h *Handle[T]) (T, error) {
+	if !pq.contains(h) {
+		var zero T
+		return zero, ErrInvalidHandle
+	}
+	return pq.removeAt(h.index), nil
+}
+
+// Contains checks if h refers to an element still in the priority queue.
+func (pq *PriorityQueue[T]) Contains(h *Handle[T]) bool {
+	return pq.contains(h)
+}
+
+// Size returns the number of elements in the priority queue.
+func (pq *PriorityQueue[T]) Size() int {
+	return len(pq.heap.items)
+}
+
+// IsEmpty checks if the priority queue is empty.
+func (pq *PriorityQueue[T]) IsEmpty() bool {
+	return len(pq.heap.items) == 0
+}
+
+// Clear removes all elements from the priority queue, invalidating their handles.
+func (pq *PriorityQueue[T]) Clear() {
+	for _, h := range pq.heap.items {
+		h.index = -1
+	}
+	pq.heap.items = nil
+}
+
+// ToSlice returns the elements of the priority queue in heap order, which is not sorted beyond the first element
+// having the highest priority.
+func (pq *PriorityQueue[T]) ToSlice() []T {
+	result := make([]T, len(pq.heap.items))
+	for i, h := range pq.heap.items {
+		result[i] = h.value
+	}
+	return result
+}
+
+func (pq *PriorityQueue[T]) contains(h *Handle[T]) bool {
+	return h != nil && h.index >= 0 && h.index < len(pq.heap.items) && pq.heap.items[h.index] == h
+}
+
+// removeAt removes the element at heap position i and invalidates its handle.
+func (pq *PriorityQueue[T]) removeAt(i int) T {
+	h := pq.heap.removeAt(i)
+	h.index = -1
+	return h.value
+}
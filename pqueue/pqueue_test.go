@@ -0,0 +1,272 @@
+package pqueue
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	pq := NewOrdered[int]()
+	if _, err := pq.Pop(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+
+	values := rand.Perm(100)
+	for _, v := range values {
+		pq.Push(v)
+	}
+	if top, err := pq.Peek(); err != nil || top != 0 {
+		t.Errorf("Expected Peek to return 0, got %v (error: %v)", top, err)
+	}
+	for i := 0; i < 100; i++ {
+		if val, err := pq.Pop(); err != nil || val != i {
+			t.Errorf("Expected Pop to return %d, got %v (error: %v)", i, val, err)
+		}
+	}
+	if !pq.IsEmpty() {
+		t.Errorf("Expected priority queue to be empty, got size %d", pq.Size())
+	}
+}
+
+func TestMaxHeap(t *testing.T) {
+	pq := New(func(a, b string) bool { return a > b })
+	for _, s := range []string{"b", "d", "a", "c"} {
+		pq.Push(s)
+	}
+	var got []string
+	for !pq.IsEmpty() {
+		s, _ := pq.Pop()
+		got = append(got, s)
+	}
+	if expected := []string{"d", "c", "b", "a"}; !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestFromSlice(t *testing.T) {
+	values := rand.Perm(50)
+	pq := FromSlice(values, func(a, b int) bool { return a < b })
+	if pq.Size() != 50 {
+		t.Errorf("Expected size to be 50, got %d", pq.Size())
+	}
+	for i := 0; i < 50; i++ {
+		if val, err := pq.Pop(); err != nil || val != i {
+			t.Errorf("Expected Pop to return %d, got %v (error: %v)", i, val, err)
+		}
+	}
+}
+
+func TestHandles(t *testing.T) {
+	type task struct {
+		name     string
+		priority int
+	}
+	pq := New(func(a, b *task) bool { return a.priority < b.priority })
+	handles := make(map[string]*Handle[*task])
+	for i, name := range []string{"a", "b", "c", "d", "e"} {
+		handles[name] = pq.Push(&task{name, i * 10})
+	}
+
+	// Move e to the front by updating, and a to the back by fixing in place
+	if err := pq.Update(handles["e"], &task{"e", -1}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	handles["a"].Value().priority = 100
+	if err := pq.Fix(handles["a"]); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if val, err := pq.Remove(handles["c"]); err != nil || val.name != "c" {
+		t.Errorf("Expected Remove to return c, got %v (error: %v)", val, err)
+	}
+	if pq.Contains(handles["c"]) {
+		t.Error("Expected removed handle not to be contained")
+	}
+	if _, err := pq.Remove(handles["c"]); !errors.Is(err, ErrInvalidHandle) {
+		t.Errorf("Expected ErrInvalidHandle, got %v", err)
+	}
+
+	var got []string
+	for !pq.IsEmpty() {
+		val, _ := pq.Pop()
+		got = append(got, val.name)
+	}
+	if expected := []string{"e", "b", "d", "a"}; !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if err := pq.Update(handles["b"], &task{"b", 0}); !errors.Is(err, ErrInvalidHandle) {
+		t.Errorf("Expected ErrInvalidHandle for popped handle, got %v", err)
+	}
+
+	// A handle from another queue is rejected even if its index is in range
+	other := NewOrdered[int]()
+	h := other.Push(1)
+	mine := NewOrdered[int]()
+	mine.Push(2)
+	if err := mine.Fix(h); !errors.Is(err, ErrInvalidHandle) {
+		t.Errorf("Expected ErrInvalidHandle for another queue's handle, got %v", err)
+	}
+}
+
+// Checks the heap against a sorted slice through a random mix of operations
+func TestRandomOperations(t *testing.T) {
+	pq := NewOrdered[int]()
+	var handles []*Handle[int]
+	var expected []int
+	for i := 0; i < 2000; i++ {
+		switch op := rand.IntN(4); {
+		case op < 2 || len(handles) == 0:
+			v := rand.IntN(1000)
+			handles = append(handles, pq.Push(v))
+			expected = append(expected, v)
+		case op == 2:
+			j := rand.IntN(len(handles))
+			v := rand.IntN(1000)
+			expected[slices.Index(expected, handles[j].Value())] = v
+			_ = pq.Update(handles[j], v)
+		default:
+			j := rand.IntN(len(handles))
+			val, err := pq.Remove(handles[j])
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expected = slices.Delete(expected, slices.Index(expected, val), slices.Index(expected, val)+1)
+			handles = slices.Delete(handles, j, j+1)
+		}
+	}
+	slices.Sort(expected)
+	for _, v := range expected {
+		if val, err := pq.Pop(); err != nil || val != v {
+			t.Fatalf("Expected Pop to return %d, got %v (error: %v)", v, val, err)
+		}
+	}
+}
+
+func TestIndexed(t *testing.T) {
+	pq := NewIndexedMin[string, int]()
+	if _, _, err := pq.Pop(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+	for i, id := range []string{"a", "b", "c", "d"} {
+		if err := pq.Push(id, (i+1)*10); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	if err := pq.Push("a", 0); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate, got %v", err)
+	}
+
+	if err := pq.DecreaseKey("d", 5); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := pq.DecreaseKey("c", 50); !errors.Is(err, ErrWrongDirection) {
+		t.Errorf("Expected ErrWrongDirection, got %v", err)
+	}
+	if err := pq.IncreaseKey("a", 25); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := pq.IncreaseKey("b", 1); !errors.Is(err, ErrWrongDirection) {
+		t.Errorf("Expected ErrWrongDirection, got %v", err)
+	}
+	if p, ok := pq.Priority("a"); !ok || p != 25 {
+		t.Errorf("Expected priority of a to be 25, got %v (ok: %v)", p, ok)
+	}
+	if p, err := pq.Remove("c"); err != nil || p != 30 {
+		t.Errorf("Expected Remove to return 30, got %v (error: %v)", p, err)
+	}
+	if pq.Contains("c") {
+		t.Error("Expected c to be removed")
+	}
+	if err := pq.Update("c", 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	var got []string
+	for !pq.IsEmpty() {
+		id, _, _ := pq.Pop()
+		got = append(got, id)
+	}
+	if expected := []string{"d", "b", "a"}; !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestIndexedMax(t *testing.T) {
+	pq := NewIndexedMax[int, float64]()
+	pq.Push(1, 1.5)
+	pq.Push(2, 2.5)
+	if err := pq.DecreaseKey(1, 3.5); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if id, p, err := pq.Peek(); err != nil || id != 1 || p != 3.5 {
+		t.Errorf("Expected Peek to return 1 with 3.5, got %v with %v (error: %v)", id, p, err)
+	}
+}
+
+// Runs Dijkstra's algorithm, the use case decrease-key exists for
+func TestIndexedDijkstra(t *testing.T) {
+	type edge struct{ to, weight int }
+	graph := map[int][]edge{
+		0: {{1, 4}, {2, 1}},
+		2: {{1, 2}, {3, 5}},
+		1: {{3, 1}},
+	}
+	dist := map[int]int{0: 0}
+	pq := NewIndexedMin[int, int]()
+	pq.Push(0, 0)
+	for !pq.IsEmpty() {
+		u, d, _ := pq.Pop()
+		for _, e := range graph[u] {
+			if old, seen := dist[e.to]; !seen || d+e.weight < old {
+				dist[e.to] = d + e.weight
+				if pq.Contains(e.to) {
+					_ = pq.DecreaseKey(e.to, d+e.weight)
+				} else {
+					_ = pq.Push(e.to, d+e.weight)
+				}
+			}
+		}
+	}
+	expected := map[int]int{0: 0, 1: 3, 2: 1, 3: 4}
+	for v, d := range expected {
+		if dist[v] != d {
+			t.Errorf("Expected distance to %d to be %d, got %d", v, d, dist[v])
+		}
+	}
+}
+
+// Checks the indexed heap against a map through a random mix of operations
+func TestIndexedRandomOperations(t *testing.T) {
+	pq := NewIndexedMin[int, int]()
+	expected := make(map[int]int)
+	for i := 0; i < 2000; i++ {
+		id := rand.IntN(100)
+		p := rand.IntN(1000)
+		switch rand.IntN(3) {
+		case 0:
+			if err := pq.Push(id, p); err == nil {
+				expected[id] = p
+			}
+		case 1:
+			if err := pq.Update(id, p); err == nil {
+				expected[id] = p
+			}
+		default:
+			if _, err := pq.Remove(id); err == nil {
+				delete(expected, id)
+			}
+		}
+	}
+	if pq.Size() != len(expected) {
+		t.Fatalf("Expected size to be %d, got %d", len(expected), pq.Size())
+	}
+	last := -1
+	for !pq.IsEmpty() {
+		id, p, _ := pq.Pop()
+		if p < last || expected[id] != p {
+			t.Fatalf("Popped %d with %d out of order or with the wrong priority", id, p)
+		}
+		last = p
+	}
+}
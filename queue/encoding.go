@@ -0,0 +1,41 @@
+package queue
+
+import "github.com/Shreyas-Adireddy/data_structures/internal/codec"
+
+// MarshalJSON encodes the queue as a JSON array from front to rear.
+func (q *Queue[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalJSON(q.ToSlice())
+}
+
+// UnmarshalJSON replaces the contents of the queue with a JSON array, the first element becoming the front.
+func (q *Queue[T]) UnmarshalJSON(data []byte) error {
+	return codec.UnmarshalJSON(data, q.replace)
+}
+
+// GobEncode encodes the queue from front to rear, independent of how it is laid out in memory.
+func (q *Queue[T]) GobEncode() ([]byte, error) {
+	return codec.GobEncode(q.ToSlice())
+}
+
+// GobDecode replaces the contents of the queue with data encoded by GobEncode.
+func (q *Queue[T]) GobDecode(data []byte) error {
+	return codec.GobDecode(data, q.replace)
+}
+
+// MarshalBinary encodes the queue with encoding/gob, same as GobEncode.
+func (q *Queue[T]) MarshalBinary() ([]byte, error) {
+	return q.GobEncode()
+}
+
+// UnmarshalBinary replaces the contents of the queue with data encoded by MarshalBinary.
+func (q *Queue[T]) UnmarshalBinary(data []byte) error {
+	return q.GobDecode(data)
+}
+
+// replace replaces the contents of the queue with values, the first becoming the front. Works on a zero Queue too. It
+// never fails, the error only matches what codec expects.
+func (q *Queue[T]) replace(values []T) error {
+	q.Clear()
+	q.EnqueueAll(values...)
+	return nil
+}
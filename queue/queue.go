@@ -1,23 +1,85 @@
 package queue
 
-import "fmt"
+import (
+	"github.com/Shreyas-Adireddy/data_structures"
+	"github.com/Shreyas-Adireddy/data_structures/internal/capacity"
+	"iter"
+	"slices"
+)
 
 const maxInt = int(^uint(0) >> 1)
 
+// ErrEmpty is returned by Dequeue, Front and Back when the queue has no elements.
+var ErrEmpty = datastructures.ErrEmpty
+
+var _ datastructures.FIFO[int] = (*Queue[int])(nil)
+
 type Queue[T any] struct {
 	data  []T
 	front int
 	rear  int
 	size  int
+	opts  options
+}
+
+// Option configures a Queue created by New.
+type Option func(*options)
+
+type options struct {
+	growth      datastructures.GrowthPolicy
+	noShrink    bool
+	minCapacity int // Capacity the automatic shrink never goes below, 8 if unset
+}
+
+// WithGrowth sets the policy used to grow the queue when it is full. Defaults to datastructures.SliceGrowth.
+func WithGrowth(policy datastructures.GrowthPolicy) Option {
+	return func(o *options) {
+		o.growth = policy
+	}
 }
 
-func New[T any]() *Queue[T] {
-	return &Queue[T]{
-		data:  make([]T, 8),
+// WithoutShrink stops Dequeue from halving the capacity when the queue is no more than 1/4 full, which avoids
+// shrink-then-regrow oscillation under bursty workloads.
+func WithoutShrink() Option {
+	return func(o *options) {
+		o.noShrink = true
+	}
+}
+
+// WithMinCapacity sets the capacity the automatic shrink never goes below, and that Clear resets the queue to. The queue
+// starts with at least this capacity. Defaults to 8, or to the capacity passed to NewWithCapacity.
+func WithMinCapacity(capacity int) Option {
+	return func(o *options) {
+		o.minCapacity = max(capacity, 1)
+	}
+}
+
+// New creates a new Queue, configured by opts.
+func New[T any](opts ...Option) *Queue[T] {
+	q := &Queue[T]{
 		front: 0,
 		rear:  0,
 		size:  0,
 	}
+	for _, opt := range opts {
+		opt(&q.opts)
+	}
+	q.data = make([]T, max(8, q.minCapacity()))
+	return q
+}
+
+// NewWithCapacity creates a new Queue with room for capacity elements before it has to grow. The automatic shrink
+// never takes it below capacity, so a preallocated queue stays allocation-free while it doesn't outgrow it.
+func NewWithCapacity[T any](capacity int, opts ...Option) *Queue[T] {
+	capacity = max(capacity, 1)
+	q := &Queue[T]{
+		opts: options{minCapacity: capacity},
+	}
+	for _, opt := range opts {
+		opt(&q.opts)
+	}
+	q.data = make([]T, max(capacity, q.minCapacity()))
+	return q
 }
 
 // Enqueue appends to the rear of the queue.
@@ -26,49 +88,73 @@ func (q *Queue[T]) Enqueue(value T) {
 		panic("queue size (which is an int) is going to overflow")
 	}
 	if q.size == len(q.data) {
-		newCapacity := q.size
-		if q.size < 256 {
-			// If the queue is full and small, double the capacity
-			newCapacity += q.size
-		} else {
-			// If the queue is full and large, gradually go from 2x -> 1.25x capacity
-			newCapacity += (q.size + 3*256) >> 2
-		}
-		if newCapacity < 0 {
-			// If overflowed, set it to max int
-			newCapacity = maxInt
-		}
-		q.Resize(newCapacity)
+		q.grow(q.size + 1)
 	}
 	q.data[q.rear] = value
 	q.rear = (q.rear + 1) % len(q.data)
 	q.size++
 }
 
-// Dequeue pops from the front of the queue. If the size becomes less than 1/4 of the capacity, half the capacity.
+// EnqueueAll appends values to the rear of the queue in order, resizing at most once.
+func (q *Queue[T]) EnqueueAll(values ...T) {
+	if len(values) > maxInt-q.size {
+		panic("queue size (which is an int) is going to overflow")
+	}
+	if q.size+len(values) > len(q.data) {
+		q.grow(q.size + len(values))
+	}
+	// Copy in at most two segments: up to the end of the array, then wrapping around to the start
+	n := copy(q.data[q.rear:], values)
+	copy(q.data, values[n:])
+	q.rear = (q.rear + len(values)) % len(q.data)
+	q.size += len(values)
+}
+
+// Dequeue pops from the front of the queue. If the size becomes less than 1/4 of the capacity, half the capacity,
+// unless the queue was created WithoutShrink.
 func (q *Queue[T]) Dequeue() (T, error) {
 	if q.size == 0 {
 		var null T
-		return null, fmt.Errorf("queue is empty")
+		return null, ErrEmpty
 	}
 	value := q.data[q.front]
+	// Clear the slot so the queue doesn't keep the value reachable
+	var zero T
+	q.data[q.front] = zero
 	q.front = (q.front + 1) % len(q.data)
 	q.size--
-	// If the size becomes less than 1/4 of the capacity, half the capacity
-	if q.size > 0 && q.size <= len(q.data)/4 {
-		newCapacity := len(q.data) / 2
-		if newCapacity < 8 {
-			newCapacity = 8
-		}
-		q.Resize(newCapacity)
-	}
+	q.shrink()
 	return value, nil
 }
 
+// DequeueN pops up to n elements from the front of the queue and returns them in order.
+func (q *Queue[T]) DequeueN(n int) []T {
+	n = max(min(n, q.size), 0)
+	result := make([]T, n)
+	q.DequeueInto(result)
+	return result
+}
+
+// DequeueInto pops elements from the front of the queue into dst until either is exhausted, and returns how many
+// were popped.
+func (q *Queue[T]) DequeueInto(dst []T) int {
+	n := min(len(dst), q.size)
+	// Copy in at most two segments: up to the end of the array, then wrapping around to the start
+	copied := copy(dst[:n], q.data[q.front:])
+	copy(dst[copied:n], q.data)
+	// Clear the vacated slots so the queue doesn't keep the values reachable
+	clear(q.data[q.front : q.front+copied])
+	clear(q.data[:n-copied])
+	q.front = (q.front + n) % len(q.data)
+	q.size -= n
+	q.shrink()
+	return n
+}
+
 func (q *Queue[T]) Front() (T, error) {
 	if q.size == 0 {
 		var null T
-		return null, fmt.Errorf("queue is empty")
+		return null, ErrEmpty
 	}
 	return q.data[q.front], nil
 }
@@ -76,7 +162,7 @@ func (q *Queue[T]) Front() (T, error) {
 func (q *Queue[T]) Back() (T, error) {
 	if q.size == 0 {
 		var null T
-		return null, fmt.Errorf("queue is empty")
+		return null, ErrEmpty
 	}
 	return q.data[(q.rear-1+len(q.data))%len(q.data)], nil
 }
@@ -85,12 +171,17 @@ func (q *Queue[T]) Size() int {
 	return q.size
 }
 
+// Cap returns the number of elements the queue holds before it has to grow.
+func (q *Queue[T]) Cap() int {
+	return len(q.data)
+}
+
 func (q *Queue[T]) IsEmpty() bool {
 	return q.size == 0
 }
 
 func (q *Queue[T]) Clear() {
-	q.data = make([]T, 8)
+	q.data = make([]T, q.minCapacity())
 	q.front = 0
 	q.rear = 0
 	q.size = 0
@@ -105,10 +196,78 @@ func (q *Queue[T]) ToSlice() []T {
 	return result
 }
 
+// Clone returns a copy of the queue with the same capacity and options. The elements themselves are copied shallowly.
+func (q *Queue[T]) Clone() *Queue[T] {
+	clone := *q
+	clone.data = slices.Clone(q.data)
+	return &clone
+}
+
+// All returns an iterator over the elements of the queue from front to rear. It walks the underlying
+// array in place, so the queue must not be modified during iteration.
+func (q *Queue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; i < q.size; i++ {
+			if !yield(q.data[(q.front+i)%len(q.data)]) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the elements of the queue from rear to front.
+func (q *Queue[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := q.size - 1; i >= 0; i-- {
+			if !yield(q.data[(q.front+i)%len(q.data)]) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns an iterator over the positions and elements of the queue, where position 0 is the front.
+func (q *Queue[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := 0; i < q.size; i++ {
+			if !yield(i, q.data[(q.front+i)%len(q.data)]) {
+				return
+			}
+		}
+	}
+}
+
+// grow resizes the queue so it holds at least minCapacity elements, according to its growth policy.
+func (q *Queue[T]) grow(minCapacity int) {
+	q.Resize(capacity.Grow(q.opts.growth, len(q.data), minCapacity))
+}
+
+// shrink halves the capacity while the queue is no more than 1/4 full, down to its minimum capacity, unless
+// shrinking is disabled.
+func (q *Queue[T]) shrink() {
+	if q.opts.noShrink {
+		return
+	}
+	if newCapacity := capacity.Shrink(q.size, len(q.data), q.minCapacity()); newCapacity < len(q.data) {
+		q.Resize(newCapacity)
+	}
+}
+
+// minCapacity returns the capacity the queue starts with after Clear and never shrinks below.
+func (q *Queue[T]) minCapacity() int {
+	if q.opts.minCapacity == 0 {
+		return 8
+	}
+	return q.opts.minCapacity
+}
+
 // Resize adjusts the capacity of the queue. However, Dequeue may half the capacity if queue in not more than 1/4-th full.
 func (q *Queue[T]) Resize(newCapacity int) {
-	if newCapacity < len(q.data) {
-		newCapacity = len(q.data)
+	if newCapacity < q.size {
+		newCapacity = q.size
+	}
+	if newCapacity < 1 {
+		newCapacity = 1
 	}
 	newData := make([]T, newCapacity)
 	for i := 0; i < q.size; i++ {
@@ -116,5 +275,6 @@ func (q *Queue[T]) Resize(newCapacity int) {
 	}
 	q.data = newData
 	q.front = 0
-	q.rear = q.size
+	// A full queue wraps its rear back to the start
+	q.rear = q.size % newCapacity
 }
@@ -1,6 +1,14 @@
 package queue
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"github.com/Shreyas-Adireddy/data_structures"
+	"github.com/Shreyas-Adireddy/data_structures/internal/gctest"
+	"runtime"
+	"slices"
 	"testing"
 )
 
@@ -33,8 +41,8 @@ func TestEnqueueOverflow(t *testing.T) {
 func TestDequeueUnderflow(t *testing.T) {
 	q := New[int]()
 	_, err := q.Dequeue()
-	if err == nil || err.Error() != "queue is empty" {
-		t.Errorf("Expected 'queue is empty' error, got: %v", err)
+	if !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got: %v", err)
 	}
 }
 
@@ -63,8 +71,8 @@ func TestRepeatedEnqueueDequeue(t *testing.T) {
 func TestEmptyQueueDequeue(t *testing.T) {
 	q := New[int]()
 	_, err := q.Dequeue()
-	if err == nil || err.Error() != "queue is empty" {
-		t.Errorf("Expected 'queue is empty' error, got: %v", err)
+	if !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got: %v", err)
 	}
 }
 
@@ -175,3 +183,272 @@ func TestMinimumCapacity(t *testing.T) {
 		t.Errorf("Expected queue capacity to be 8 after resizing, got: %v", capacity)
 	}
 }
+
+func TestIterators(t *testing.T) {
+	q := New[int]()
+	// Wrap the ring so the iterators have to cross the end of the array
+	for i := 0; i < 6; i++ {
+		q.Enqueue(i)
+	}
+	for i := 0; i < 4; i++ {
+		_, _ = q.Dequeue()
+	}
+	for i := 6; i < 12; i++ {
+		q.Enqueue(i)
+	}
+
+	if got := slices.Collect(q.All()); !slices.Equal(got, q.ToSlice()) {
+		t.Errorf("All returned %v, expected %v", got, q.ToSlice())
+	}
+	if got, expected := slices.Collect(q.Backward()), []int{11, 10, 9, 8, 7, 6, 5, 4}; !slices.Equal(got, expected) {
+		t.Errorf("Backward returned %v, expected %v", got, expected)
+	}
+	for i, val := range q.Enumerate() {
+		if val != i+4 {
+			t.Errorf("Enumerate returned %v at %v, expected %v", val, i, i+4)
+		}
+		if i == 2 {
+			break
+		}
+	}
+}
+
+func TestBatchOperations(t *testing.T) {
+	q := New[int]()
+	// Move the front so the batches wrap around the end of the array
+	q.EnqueueAll(0, 1, 2, 3, 4, 5)
+	if got := q.DequeueN(4); !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Errorf("DequeueN returned %v, expected %v", got, []int{0, 1, 2, 3})
+	}
+	q.EnqueueAll(6, 7, 8, 9)
+	if expected := []int{4, 5, 6, 7, 8, 9}; !slices.Equal(q.ToSlice(), expected) {
+		t.Errorf("Expected %v, got %v", expected, q.ToSlice())
+	}
+
+	// Force a resize in the middle of the batch
+	values := make([]int, 100)
+	for i := range values {
+		values[i] = i + 10
+	}
+	q.EnqueueAll(values...)
+	if q.Size() != 106 {
+		t.Errorf("Expected queue size to be 106, got %d", q.Size())
+	}
+
+	dst := make([]int, 3)
+	if n := q.DequeueInto(dst); n != 3 || !slices.Equal(dst, []int{4, 5, 6}) {
+		t.Errorf("DequeueInto returned %d and %v, expected 3 and %v", n, dst, []int{4, 5, 6})
+	}
+	rest := q.DequeueN(1000)
+	if len(rest) != 103 || rest[0] != 7 || rest[102] != 109 {
+		t.Errorf("DequeueN returned %d elements from %v to %v, expected 103 from 7 to 109", len(rest), rest[0], rest[len(rest)-1])
+	}
+	if !q.IsEmpty() || len(q.DequeueN(5)) != 0 || q.DequeueInto(dst) != 0 {
+		t.Error("Expected queue to be empty")
+	}
+}
+
+func TestGrowthPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		capacities []int
+	}{
+		{"Default", nil, []int{8, 16, 32, 64, 128, 256, 512}},
+		{"Doubling", []Option{WithGrowth(datastructures.DoublingGrowth)}, []int{8, 16, 32, 64, 128, 256, 512}},
+		{"Chunk", []Option{WithGrowth(datastructures.ChunkGrowth(100))}, []int{8, 108, 208, 308, 408, 508}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := New[int](tt.opts...)
+			var capacities []int
+			for i := 0; i < 500; i++ {
+				if len(capacities) == 0 || capacities[len(capacities)-1] != len(q.data) {
+					capacities = append(capacities, len(q.data))
+				}
+				q.Enqueue(i)
+			}
+			capacities = append(capacities, len(q.data))
+			if !slices.Equal(slices.Compact(capacities), tt.capacities) {
+				t.Errorf("Expected capacities %v, got %v", tt.capacities, slices.Compact(capacities))
+			}
+		})
+	}
+
+	t.Run("WithoutShrink", func(t *testing.T) {
+		q := New[int](WithoutShrink())
+		for i := 0; i < 100; i++ {
+			q.Enqueue(i)
+		}
+		for i := 0; i < 99; i++ {
+			_, _ = q.Dequeue()
+		}
+		if capacity := len(q.data); capacity != 128 {
+			t.Errorf("Expected queue capacity to stay 128, got: %v", capacity)
+		}
+	})
+}
+
+func TestNewWithCapacity(t *testing.T) {
+	q := NewWithCapacity[int](64)
+	if q.Cap() != 64 {
+		t.Errorf("Expected queue capacity to be 64, got: %v", q.Cap())
+	}
+	allocs := testing.AllocsPerRun(10, func() {
+		for i := 0; i < 64; i++ {
+			q.Enqueue(i)
+		}
+		for i := 0; i < 63; i++ {
+			_, _ = q.Dequeue()
+		}
+		_, _ = q.Dequeue()
+	})
+	if allocs != 0 {
+		t.Errorf("Expected a preallocated queue not to allocate, got %v allocations", allocs)
+	}
+	if q.Cap() != 64 {
+		t.Errorf("Expected queue capacity to stay 64, got: %v", q.Cap())
+	}
+
+	// Growing past the preallocated capacity still works, and shrinking stops at it
+	for i := 0; i < 1000; i++ {
+		q.Enqueue(i)
+	}
+	for i := 0; i < 999; i++ {
+		_, _ = q.Dequeue()
+	}
+	if q.Cap() != 64 {
+		t.Errorf("Expected queue capacity to shrink back to 64, got: %v", q.Cap())
+	}
+	q.Clear()
+	if q.Cap() != 64 {
+		t.Errorf("Expected queue capacity to be 64 after Clear, got: %v", q.Cap())
+	}
+}
+
+func TestWithMinCapacity(t *testing.T) {
+	q := New[int](WithMinCapacity(32))
+	if q.Cap() != 32 {
+		t.Errorf("Expected queue capacity to be 32, got: %v", q.Cap())
+	}
+	for i := 0; i < 1000; i++ {
+		q.Enqueue(i)
+	}
+	for i := 0; i < 1000; i++ {
+		_, _ = q.Dequeue()
+		if q.Cap() < 32 {
+			t.Fatalf("Expected Dequeue never to shrink the queue below 32, got: %v", q.Cap())
+		}
+	}
+	if q.Cap() != 32 {
+		t.Errorf("Expected queue capacity to shrink back to 32, got: %v", q.Cap())
+	}
+
+	// The option overrides the floor NewWithCapacity sets
+	q = NewWithCapacity[int](8, WithMinCapacity(16))
+	for i := 0; i < 100; i++ {
+		q.Enqueue(i)
+	}
+	for i := 0; i < 100; i++ {
+		_, _ = q.Dequeue()
+	}
+	if q.Cap() != 16 {
+		t.Errorf("Expected queue capacity to shrink back to 16, got: %v", q.Cap())
+	}
+}
+
+func TestRemovedElementsAreReleased(t *testing.T) {
+	q := New[*gctest.Object]()
+	// Wrap the ring so DequeueInto clears both of the segments it copies from
+	q.EnqueueAll(nil, nil, nil, nil, nil, nil)
+	q.DequeueN(6)
+	var collected []func() bool
+	for i := 0; i < 5; i++ {
+		obj, freed := gctest.Track()
+		q.Enqueue(obj)
+		collected = append(collected, freed)
+	}
+	q.Enqueue(&gctest.Object{})
+	q.Dequeue()
+	q.DequeueInto(make([]*gctest.Object, 4))
+	for i, freed := range collected {
+		if !freed() {
+			t.Errorf("Expected dequeued element %d to be garbage collected", i)
+		}
+	}
+	runtime.KeepAlive(q)
+}
+
+func TestEncoding(t *testing.T) {
+	q := New[int]()
+	for i := 0; i < 6; i++ {
+		q.Enqueue(i)
+	}
+	for i := 0; i < 4; i++ {
+		_, _ = q.Dequeue()
+	}
+	// Wrap around the end of the array so the logical order differs from the memory layout
+	q.EnqueueAll(6, 7, 8, 9, 10)
+	expected := []int{4, 5, 6, 7, 8, 9, 10}
+
+	data, err := json.Marshal(q)
+	if err != nil || string(data) != "[4,5,6,7,8,9,10]" {
+		t.Errorf("Expected MarshalJSON to return [4,5,6,7,8,9,10], got %s (error: %v)", data, err)
+	}
+	// Decoding into a zero value leaves a usable queue
+	var fromJSON Queue[int]
+	if err := json.Unmarshal(data, &fromJSON); err != nil || !slices.Equal(fromJSON.ToSlice(), expected) {
+		t.Errorf("Expected UnmarshalJSON to restore %v, got %v (error: %v)", expected, fromJSON.ToSlice(), err)
+	}
+	fromJSON.Enqueue(11)
+	if fromJSON.Size() != len(expected)+1 {
+		t.Errorf("Expected size to be %d after decoding and adding, got %d", len(expected)+1, fromJSON.Size())
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(q); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fromGob := New[int]()
+	fromGob.Enqueue(11)
+	if err := gob.NewDecoder(&buf).Decode(fromGob); err != nil || !slices.Equal(fromGob.ToSlice(), expected) {
+		t.Errorf("Expected GobDecode to replace the contents with %v, got %v (error: %v)", expected, fromGob.ToSlice(), err)
+	}
+
+	data, err = q.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var fromBinary Queue[int]
+	if err := fromBinary.UnmarshalBinary(data); err != nil || !slices.Equal(fromBinary.ToSlice(), expected) {
+		t.Errorf("Expected UnmarshalBinary to restore %v, got %v (error: %v)", expected, fromBinary.ToSlice(), err)
+	}
+	if err := fromBinary.UnmarshalJSON([]byte("{")); err == nil {
+		t.Error("Expected UnmarshalJSON to fail on invalid JSON")
+	}
+}
+
+func TestClone(t *testing.T) {
+	q := New[int]()
+	q.EnqueueAll(1, 2, 3)
+	clone := q.Clone()
+	q.Dequeue()
+	clone.Enqueue(4)
+	if got := q.ToSlice(); !slices.Equal(got, []int{2, 3}) {
+		t.Errorf("Expected the original to hold [2 3], got %v", got)
+	}
+	if got := clone.ToSlice(); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Errorf("Expected the clone to hold [1 2 3 4], got %v", got)
+	}
+}
+
+func TestResizeToSize(t *testing.T) {
+	q := New[int]()
+	q.EnqueueAll(1, 2, 3)
+	q.Resize(q.Size())
+	q.Dequeue()
+	q.Enqueue(4)
+	if got := q.ToSlice(); !slices.Equal(got, []int{2, 3, 4}) {
+		t.Errorf("Expected [2 3 4] after resizing to the size, got %v", got)
+	}
+}
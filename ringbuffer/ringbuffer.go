@@ -0,0 +1,143 @@
+package ringbuffer
+
+import (
+	"github.com/Shreyas-Adireddy/data_structures"
+	"iter"
+)
+
+// ErrEmpty is returned by Dequeue, Front and Back when the ring buffer has no elements.
+var ErrEmpty = datastructures.ErrEmpty
+
+// RingBuffer is a fixed-capacity queue that keeps the most recent elements. It uses the same circular array layout
+// as queue.Queue, but instead of growing when full, Enqueue evicts the oldest element.
+type RingBuffer[T any] struct {
+	data    []T
+	front   int
+	rear    int
+	size    int
+	onEvict func(T)
+}
+
+// New creates a new RingBuffer holding at most capacity elements.
+func New[T any](capacity int) *RingBuffer[T] {
+	if capacity < 1 {
+		panic("ringbuffer: capacity must be positive")
+	}
+	return &RingBuffer[T]{
+		data: make([]T, capacity),
+	}
+}
+
+// OnEvict sets a callback that receives every element Enqueue evicts. Pass nil to remove it.
+func (rb *RingBuffer[T]) OnEvict(fn func(T)) {
+	rb.onEvict = fn
+}
+
+// Enqueue appends to the rear of the ring buffer. If it is full, the front element is evicted to make room and
+// returned with ok set to true.
+func (rb *RingBuffer[T]) Enqueue(value T) (evicted T, ok bool) {
+	if rb.size == len(rb.data) {
+		evicted, ok = rb.data[rb.front], true
+		rb.front = (rb.front + 1) % len(rb.data)
+		rb.size--
+	}
+	rb.data[rb.rear] = value
+	rb.rear = (rb.rear + 1) % len(rb.data)
+	rb.size++
+	if ok && rb.onEvict != nil {
+		rb.onEvict(evicted)
+	}
+	return evicted, ok
+}
+
+// Dequeue pops from the front of the ring buffer.
+func (rb *RingBuffer[T]) Dequeue() (T, error) {
+	if rb.size == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	value := rb.data[rb.front]
+	// Clear the slot so the ring buffer doesn't keep the value reachable
+	var zero T
+	rb.data[rb.front] = zero
+	rb.front = (rb.front + 1) % len(rb.data)
+	rb.size--
+	return value, nil
+}
+
+// Front returns the oldest element without removing it.
+func (rb *RingBuffer[T]) Front() (T, error) {
+	if rb.size == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return rb.data[rb.front], nil
+}
+
+// Back returns the newest element without removing it.
+func (rb *RingBuffer[T]) Back() (T, error) {
+	if rb.size == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return rb.data[(rb.rear-1+len(rb.data))%len(rb.data)], nil
+}
+
+// Size returns the number of elements in the ring buffer.
+func (rb *RingBuffer[T]) Size() int {
+	return rb.size
+}
+
+// Cap returns the number of elements the ring buffer holds before it starts evicting.
+func (rb *RingBuffer[T]) Cap() int {
+	return len(rb.data)
+}
+
+// IsEmpty checks if the ring buffer is empty.
+func (rb *RingBuffer[T]) IsEmpty() bool {
+	return rb.size == 0
+}
+
+// IsFull checks if the next Enqueue will evict an element.
+func (rb *RingBuffer[T]) IsFull() bool {
+	return rb.size == len(rb.data)
+}
+
+// Clear removes all elements from the ring buffer, keeping its capacity. The eviction callback is not called.
+func (rb *RingBuffer[T]) Clear() {
+	rb.data = make([]T, len(rb.data))
+	rb.front = 0
+	rb.rear = 0
+	rb.size = 0
+}
+
+// ToSlice converts the ring buffer to a slice, oldest first, and returns it.
+func (rb *RingBuffer[T]) ToSlice() []T {
+	result := make([]T, rb.size)
+	for i := 0; i < rb.size; i++ {
+		result[i] = rb.data[(rb.front+i)%len(rb.data)]
+	}
+	return result
+}
+
+// All returns an iterator over the elements of the ring buffer from oldest to newest.
+func (rb *RingBuffer[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; i < rb.size; i++ {
+			if !yield(rb.data[(rb.front+i)%len(rb.data)]) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the elements of the ring buffer from newest to oldest.
+func (rb *RingBuffer[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := rb.size - 1; i >= 0; i-- {
+			if !yield(rb.data[(rb.front+i)%len(rb.data)]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package ringbuffer
+
+import (
+	"errors"
+	"github.com/Shreyas-Adireddy/data_structures/internal/gctest"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestRingBuffer(t *testing.T) {
+	rb := New[int](3)
+	var evictedByCallback []int
+	rb.OnEvict(func(v int) {
+		evictedByCallback = append(evictedByCallback, v)
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, ok := rb.Enqueue(i); ok {
+			t.Errorf("Enqueue(%d) evicted an element before the ring buffer was full", i)
+		}
+	}
+	if !rb.IsFull() {
+		t.Error("Expected ring buffer to be full")
+	}
+
+	for i := 3; i < 7; i++ {
+		evicted, ok := rb.Enqueue(i)
+		if !ok || evicted != i-3 {
+			t.Errorf("Enqueue(%d) evicted %v (ok: %v), expected %v", i, evicted, ok, i-3)
+		}
+	}
+	if expected := []int{0, 1, 2, 3}; !slices.Equal(evictedByCallback, expected) {
+		t.Errorf("Expected callback to see %v, got %v", expected, evictedByCallback)
+	}
+	if expected := []int{4, 5, 6}; !slices.Equal(rb.ToSlice(), expected) {
+		t.Errorf("Expected %v, got %v", expected, rb.ToSlice())
+	}
+	if got, expected := slices.Collect(rb.Backward()), []int{6, 5, 4}; !slices.Equal(got, expected) {
+		t.Errorf("Backward returned %v, expected %v", got, expected)
+	}
+	if front, err := rb.Front(); err != nil || front != 4 {
+		t.Errorf("Front returned %v (error: %v), expected 4", front, err)
+	}
+	if back, err := rb.Back(); err != nil || back != 6 {
+		t.Errorf("Back returned %v (error: %v), expected 6", back, err)
+	}
+
+	if val, err := rb.Dequeue(); err != nil || val != 4 {
+		t.Errorf("Dequeue returned %v (error: %v), expected 4", val, err)
+	}
+	if rb.Size() != 2 || rb.Cap() != 3 {
+		t.Errorf("Expected size 2 and capacity 3, got %d and %d", rb.Size(), rb.Cap())
+	}
+
+	rb.Clear()
+	if _, err := rb.Dequeue(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+}
+
+func TestRemovedElementsAreReleased(t *testing.T) {
+	rb := New[*gctest.Object](4)
+	// Wrap the ring so the dequeued element doesn't sit in the first slot
+	rb.Enqueue(nil)
+	rb.Enqueue(nil)
+	rb.Dequeue()
+	rb.Dequeue()
+	obj, freed := gctest.Track()
+	rb.Enqueue(obj)
+	rb.Enqueue(&gctest.Object{})
+	obj = nil
+	rb.Dequeue()
+	if !freed() {
+		t.Error("Expected dequeued element to be garbage collected")
+	}
+	runtime.KeepAlive(rb)
+}
@@ -0,0 +1,41 @@
+package stack
+
+import "github.com/Shreyas-Adireddy/data_structures/internal/codec"
+
+// MarshalJSON encodes the stack as a JSON array from bottom to top.
+func (s *Stack[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalJSON(s.ToSlice())
+}
+
+// UnmarshalJSON replaces the contents of the stack with a JSON array, the last element ending up on top.
+func (s *Stack[T]) UnmarshalJSON(data []byte) error {
+	return codec.UnmarshalJSON(data, s.replace)
+}
+
+// GobEncode encodes the stack from bottom to top, independent of how it is laid out in memory.
+func (s *Stack[T]) GobEncode() ([]byte, error) {
+	return codec.GobEncode(s.ToSlice())
+}
+
+// GobDecode replaces the contents of the stack with data encoded by GobEncode.
+func (s *Stack[T]) GobDecode(data []byte) error {
+	return codec.GobDecode(data, s.replace)
+}
+
+// MarshalBinary encodes the stack with encoding/gob, same as GobEncode.
+func (s *Stack[T]) MarshalBinary() ([]byte, error) {
+	return s.GobEncode()
+}
+
+// UnmarshalBinary replaces the contents of the stack with data encoded by MarshalBinary.
+func (s *Stack[T]) UnmarshalBinary(data []byte) error {
+	return s.GobDecode(data)
+}
+
+// replace replaces the contents of the stack with values, the last element ending up on top. Works on a zero Stack too.
+// It never fails, the error only matches what codec expects.
+func (s *Stack[T]) replace(values []T) error {
+	s.Clear()
+	s.PushAll(values...)
+	return nil
+}
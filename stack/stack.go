@@ -1,6 +1,14 @@
 package stack
 
-import "errors"
+import (
+	"github.com/Shreyas-Adireddy/data_structures"
+	"iter"
+)
+
+// ErrEmpty is returned by Pop and Peek when the stack has no elements.
+var ErrEmpty = datastructures.ErrEmpty
+
+var _ datastructures.LIFO[int] = (*Stack[int])(nil)
 
 // Stack represents a stack data structure.
 type Stack[T any] struct {
@@ -12,27 +20,53 @@ func New[T any]() *Stack[T] {
 	return &Stack[T]{elements: []T{}}
 }
 
+// NewWithCapacity creates a new Stack with room for capacity elements before it has to grow.
+func NewWithCapacity[T any](capacity int) *Stack[T] {
+	return &Stack[T]{elements: make([]T, 0, max(capacity, 0))}
+}
+
 // Push adds an element to the stack.
 func (s *Stack[T]) Push(element T) {
 	s.elements = append(s.elements, element)
 }
 
+// PushAll adds values to the stack in order, so the last one ends up on top.
+func (s *Stack[T]) PushAll(values ...T) {
+	s.elements = append(s.elements, values...)
+}
+
 // Pop removes and returns the top element of the stack.
 func (s *Stack[T]) Pop() (T, error) {
 	if len(s.elements) == 0 {
 		var zero T
-		return zero, errors.New("stack is empty")
+		return zero, ErrEmpty
 	}
 	element := s.elements[len(s.elements)-1]
+	// Clear the slot so the stack doesn't keep the element reachable
+	var zero T
+	s.elements[len(s.elements)-1] = zero
 	s.elements = s.elements[:len(s.elements)-1]
 	return element, nil
 }
 
+// PopN removes up to n elements from the top of the stack and returns them in the order Pop would, top first.
+func (s *Stack[T]) PopN(n int) []T {
+	n = max(min(n, len(s.elements)), 0)
+	top := s.elements[len(s.elements)-n:]
+	result := make([]T, n)
+	for i, element := range top {
+		result[n-1-i] = element
+	}
+	clear(top)
+	s.elements = s.elements[:len(s.elements)-n]
+	return result
+}
+
 // Peek returns the top element of the stack without removing it.
 func (s *Stack[T]) Peek() (T, error) {
 	if len(s.elements) == 0 {
 		var zero T
-		return zero, errors.New("stack is empty")
+		return zero, ErrEmpty
 	}
 	return s.elements[len(s.elements)-1], nil
 }
@@ -47,6 +81,40 @@ func (s *Stack[T]) ToSlice() []T {
 	return result
 }
 
+// All returns an iterator over the elements of the stack from bottom to top, the same order as ToSlice.
+// The stack must not be modified during iteration.
+func (s *Stack[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, element := range s.elements {
+			if !yield(element) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the elements of the stack from top to bottom, the order Pop would return them.
+func (s *Stack[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := len(s.elements) - 1; i >= 0; i-- {
+			if !yield(s.elements[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns an iterator over the positions and elements of the stack, where position 0 is the bottom.
+func (s *Stack[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, element := range s.elements {
+			if !yield(i, element) {
+				return
+			}
+		}
+	}
+}
+
 // IsEmpty checks if the stack is empty.
 func (s *Stack[T]) IsEmpty() bool {
 	return len(s.elements) == 0
@@ -57,7 +125,14 @@ func (s *Stack[T]) Size() int {
 	return len(s.elements)
 }
 
+// Cap returns the number of elements the stack holds before it has to grow.
+func (s *Stack[T]) Cap() int {
+	return cap(s.elements)
+}
+
+// Clear removes all elements from the stack, keeping its capacity.
 func (s *Stack[T]) Clear() {
+	clear(s.elements)
 	s.elements = s.elements[:0]
 }
 
@@ -1,6 +1,14 @@
 package stack
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"github.com/Shreyas-Adireddy/data_structures"
+	"github.com/Shreyas-Adireddy/data_structures/internal/gctest"
+	"runtime"
+	"slices"
 	"testing"
 )
 
@@ -38,3 +46,125 @@ func TestStack(t *testing.T) {
 		t.Errorf("Expected stack size to be 1, got %d", s.Size())
 	}
 }
+
+func TestEmptyStack(t *testing.T) {
+	s := New[int]()
+	if _, err := s.Pop(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+	if _, err := s.Peek(); !errors.Is(err, datastructures.ErrEmpty) {
+		t.Errorf("Expected datastructures.ErrEmpty, got %v", err)
+	}
+}
+
+func TestIterators(t *testing.T) {
+	s := New[int]()
+	s.Push(1)
+	s.Push(2)
+	s.Push(3)
+	if got, expected := slices.Collect(s.All()), []int{1, 2, 3}; !slices.Equal(got, expected) {
+		t.Errorf("All returned %v, expected %v", got, expected)
+	}
+	if got, expected := slices.Collect(s.Backward()), []int{3, 2, 1}; !slices.Equal(got, expected) {
+		t.Errorf("Backward returned %v, expected %v", got, expected)
+	}
+	for i, val := range s.Enumerate() {
+		if val != i+1 {
+			t.Errorf("Enumerate returned %v at %v, expected %v", val, i, i+1)
+		}
+	}
+}
+
+func TestBatchOperations(t *testing.T) {
+	s := New[int]()
+	s.PushAll(1, 2, 3, 4)
+	if top, _ := s.Peek(); top != 4 {
+		t.Errorf("Expected top element to be 4, got %v", top)
+	}
+	if got, expected := s.PopN(3), []int{4, 3, 2}; !slices.Equal(got, expected) {
+		t.Errorf("PopN returned %v, expected %v", got, expected)
+	}
+	if got, expected := s.PopN(3), []int{1}; !slices.Equal(got, expected) {
+		t.Errorf("PopN returned %v, expected %v", got, expected)
+	}
+}
+
+func TestNewWithCapacity(t *testing.T) {
+	s := NewWithCapacity[int](16)
+	if s.Cap() != 16 {
+		t.Errorf("Expected stack capacity to be 16, got %d", s.Cap())
+	}
+	allocs := testing.AllocsPerRun(10, func() {
+		for i := 0; i < 16; i++ {
+			s.Push(i)
+		}
+		s.Clear()
+	})
+	if allocs != 0 {
+		t.Errorf("Expected a preallocated stack not to allocate, got %v allocations", allocs)
+	}
+}
+
+func TestRemovedElementsAreReleased(t *testing.T) {
+	removals := map[string]func(s *Stack[*gctest.Object]){
+		"Pop":   func(s *Stack[*gctest.Object]) { _, _ = s.Pop() },
+		"PopN":  func(s *Stack[*gctest.Object]) { s.PopN(1) },
+		"Clear": func(s *Stack[*gctest.Object]) { s.Clear() },
+	}
+	for name, remove := range removals {
+		t.Run(name, func(t *testing.T) {
+			s := New[*gctest.Object]()
+			s.Push(&gctest.Object{})
+			obj, freed := gctest.Track()
+			s.Push(obj)
+			obj = nil
+			remove(s)
+			if !freed() {
+				t.Error("Expected removed element to be garbage collected")
+			}
+			runtime.KeepAlive(s)
+		})
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	s := New[int]()
+	s.PushAll(0, 1, 2, 3)
+	expected := []int{0, 1, 2, 3}
+
+	data, err := json.Marshal(s)
+	if err != nil || string(data) != "[0,1,2,3]" {
+		t.Errorf("Expected MarshalJSON to return [0,1,2,3], got %s (error: %v)", data, err)
+	}
+	// Decoding into a zero value leaves a usable stack
+	var fromJSON Stack[int]
+	if err := json.Unmarshal(data, &fromJSON); err != nil || !slices.Equal(fromJSON.ToSlice(), expected) {
+		t.Errorf("Expected UnmarshalJSON to restore %v, got %v (error: %v)", expected, fromJSON.ToSlice(), err)
+	}
+	fromJSON.Push(4)
+	if fromJSON.Size() != len(expected)+1 {
+		t.Errorf("Expected size to be %d after decoding and adding, got %d", len(expected)+1, fromJSON.Size())
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fromGob := New[int]()
+	fromGob.Push(4)
+	if err := gob.NewDecoder(&buf).Decode(fromGob); err != nil || !slices.Equal(fromGob.ToSlice(), expected) {
+		t.Errorf("Expected GobDecode to replace the contents with %v, got %v (error: %v)", expected, fromGob.ToSlice(), err)
+	}
+
+	data, err = s.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var fromBinary Stack[int]
+	if err := fromBinary.UnmarshalBinary(data); err != nil || !slices.Equal(fromBinary.ToSlice(), expected) {
+		t.Errorf("Expected UnmarshalBinary to restore %v, got %v (error: %v)", expected, fromBinary.ToSlice(), err)
+	}
+	if err := fromBinary.UnmarshalJSON([]byte("{")); err == nil {
+		t.Error("Expected UnmarshalJSON to fail on invalid JSON")
+	}
+}
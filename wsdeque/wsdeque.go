@@ -0,0 +1,129 @@
+// Package wsdeque provides a Chase-Lev work-stealing deque for task schedulers. One owner goroutine pushes and pops
+// tasks at the bottom without locks, while any number of thief goroutines steal from the top.
+package wsdeque
+
+import (
+	"github.com/Shreyas-Adireddy/data_structures"
+	"sync/atomic"
+)
+
+// ErrEmpty is returned by PopBottom and Steal when the deque has no elements.
+var ErrEmpty = datastructures.ErrEmpty
+
+// ring is a circular array like the one under deque.Deque, indexed by ever-increasing positions. Its length is a
+// power of two so positions map to slots with a mask. Slots hold pointers so thieves can read them atomically.
+type ring[T any] struct {
+	slots []atomic.Pointer[T]
+	mask  int64
+}
+
+func newRing[T any](capacity int64) *ring[T] {
+	return &ring[T]{
+		slots: make([]atomic.Pointer[T], capacity),
+		mask:  capacity - 1,
+	}
+}
+
+func (r *ring[T]) get(i int64) *T {
+	return r.slots[i&r.mask].Load()
+}
+
+func (r *ring[T]) put(i int64, value *T) {
+	r.slots[i&r.mask].Store(value)
+}
+
+// grow returns a ring twice the size holding the elements between top and bottom at the same positions.
+func (r *ring[T]) grow(top, bottom int64) *ring[T] {
+	bigger := newRing[T](2 * int64(len(r.slots)))
+	for i := top; i < bottom; i++ {
+		bigger.put(i, r.get(i))
+	}
+	return bigger
+}
+
+// Deque is a Chase-Lev work-stealing deque. PushBottom and PopBottom may only be called by the goroutine that owns
+// the deque; Steal, Size and IsEmpty may be called by any goroutine.
+//
+// Elements live at positions [top, bottom). The owner moves bottom, thieves move top with a compare-and-swap, and
+// the only contention between them is over the last element, which the owner also claims with a compare-and-swap.
+type Deque[T any] struct {
+	top    atomic.Int64
+	bottom atomic.Int64
+	array  atomic.Pointer[ring[T]]
+}
+
+// New creates a new Deque.
+func New[T any]() *Deque[T] {
+	d := &Deque[T]{}
+	d.array.Store(newRing[T](8))
+	return d
+}
+
+// PushBottom adds an element to the bottom of the deque, doubling the underlying array if it is full. Owner only.
+func (d *Deque[T]) PushBottom(value T) {
+	b := d.bottom.Load()
+	t := d.top.Load()
+	a := d.array.Load()
+	if b-t >= int64(len(a.slots)) {
+		// Thieves still reading the old array see the same elements at the same positions
+		a = a.grow(t, b)
+		d.array.Store(a)
+	}
+	a.put(b, &value)
+	d.bottom.Store(b + 1)
+}
+
+// PopBottom removes and returns the most recently pushed element. Owner only.
+func (d *Deque[T]) PopBottom() (T, error) {
+	var zero T
+	b := d.bottom.Load() - 1
+	a := d.array.Load()
+	// Claim position b before looking at top, so a thief that reads top afterwards sees it's taken
+	d.bottom.Store(b)
+	t := d.top.Load()
+	if t > b {
+		d.bottom.Store(b + 1)
+		return zero, ErrEmpty
+	}
+	value := a.get(b)
+	if t == b {
+		// Last element, race the thieves for it
+		won := d.top.CompareAndSwap(t, t+1)
+		d.bottom.Store(b + 1)
+		if !won {
+			return zero, ErrEmpty
+		}
+	}
+	// Only the owner writes slots, so clearing it can't race with a push
+	a.put(b, nil)
+	return *value, nil
+}
+
+// Steal removes and returns the oldest element. Safe to call from any goroutine.
+func (d *Deque[T]) Steal() (T, error) {
+	for {
+		t := d.top.Load()
+		b := d.bottom.Load()
+		if t >= b {
+			var zero T
+			return zero, ErrEmpty
+		}
+		value := d.array.Load().get(t)
+		if d.top.CompareAndSwap(t, t+1) {
+			return *value, nil
+		}
+		// Lost the race to another thief or the owner, try again
+	}
+}
+
+// Size returns the number of elements in the deque. Under concurrent use it is only a snapshot.
+func (d *Deque[T]) Size() int {
+	b := d.bottom.Load()
+	t := d.top.Load()
+	return int(max(b-t, 0))
+}
+
+// IsEmpty checks if the deque is empty.
+func (d *Deque[T]) IsEmpty() bool {
+	return d.Size() == 0
+}
@@ -0,0 +1,96 @@
+package wsdeque
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDeque(t *testing.T) {
+	d := New[int]()
+	if _, err := d.PopBottom(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+	if _, err := d.Steal(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+
+	// Push past the initial capacity so the array grows
+	for i := 0; i < 20; i++ {
+		d.PushBottom(i)
+	}
+	if d.Size() != 20 {
+		t.Errorf("Expected size to be 20, got %d", d.Size())
+	}
+	if val, err := d.Steal(); err != nil || val != 0 {
+		t.Errorf("Expected Steal to return 0, got %v (error: %v)", val, err)
+	}
+	if val, err := d.PopBottom(); err != nil || val != 19 {
+		t.Errorf("Expected PopBottom to return 19, got %v (error: %v)", val, err)
+	}
+	for i := 18; i >= 1; i-- {
+		if val, err := d.PopBottom(); err != nil || val != i {
+			t.Errorf("Expected PopBottom to return %d, got %v (error: %v)", i, val, err)
+		}
+	}
+	if !d.IsEmpty() {
+		t.Errorf("Expected deque to be empty, got size %d", d.Size())
+	}
+	d.PushBottom(1)
+	if val, err := d.PopBottom(); err != nil || val != 1 {
+		t.Errorf("Expected PopBottom to return 1 after emptying, got %v (error: %v)", val, err)
+	}
+}
+
+// Stress test meant to be run with -race: every pushed task must be taken exactly once, by the owner or a thief.
+func TestOwnerAndThieves(t *testing.T) {
+	const tasks, thieves = 50000, 4
+	d := New[int]()
+	taken := make([]atomic.Int32, tasks)
+	var remaining atomic.Int64
+	remaining.Store(tasks)
+	take := func(val int) {
+		if taken[val].Add(1) != 1 {
+			t.Errorf("Task %d was taken twice", val)
+		}
+		remaining.Add(-1)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < thieves; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for remaining.Load() > 0 {
+				if val, err := d.Steal(); err == nil {
+					take(val)
+				}
+			}
+		}()
+	}
+
+	// The owner pushes in bursts and pops some tasks back, so it keeps racing thieves for the last element
+	for i := 0; i < tasks; i++ {
+		d.PushBottom(i)
+		if i%3 == 0 {
+			if val, err := d.PopBottom(); err == nil {
+				take(val)
+			}
+		}
+	}
+	for {
+		val, err := d.PopBottom()
+		if err != nil {
+			break
+		}
+		take(val)
+	}
+	wg.Wait()
+
+	for i := range taken {
+		if taken[i].Load() != 1 {
+			t.Fatalf("Task %d was taken %d times", i, taken[i].Load())
+		}
+	}
+}